@@ -97,7 +97,7 @@ func main() {
 		4, 0, 3, 7, // bottom
 	}
 
-	shader = NewElementShader(&cube, indices, vertexShaderSource, fragmentShaderSource)
+	shader = NewElementShaderWithMode(&cube, indices, gl.QUADS, vertexShaderSource, fragmentShaderSource)
 
 	app.Start()
 }
@@ -122,7 +122,8 @@ func draw(app *App) {
 	model := mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0})
 	shader.Model.UniformMatrix4fv(false, model)
 
-	gl.DrawElements(gl.QUADS, 24, gl.UNSIGNED_INT, nil)
+	// the quads were uploaded as triangles
+	gl.DrawElements(shader.Mode, shader.Count, gl.UNSIGNED_INT, nil)
 
 	shader.Unuse()
 }
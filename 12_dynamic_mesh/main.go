@@ -2,7 +2,6 @@ package main
 
 import (
 	"math"
-	"unsafe"
 
 	. "github.com/JamesClonk/opengl/_includes"
 	"github.com/go-gl/gl"
@@ -78,13 +77,14 @@ func main() {
 		indices = append(indices, int32((i+1)*h))
 	}
 
-	shader = NewDynamicShader(&vertices, indices, vertexShaderSource, fragmentShaderSource)
+	shader = NewDynamicShaderWithMode(&vertices, indices, gl.TRIANGLE_STRIP, vertexShaderSource, fragmentShaderSource)
 
 	app.Start()
 }
 
 func draw(app *App) {
-	time += 0.05
+	// app time is simulated while recording (F11), so recordings play smoothly
+	time = app.Time * 3
 
 	shader.Use()
 
@@ -107,9 +107,8 @@ func draw(app *App) {
 		vertices[i].Color = mgl.Vec4{1, 0, 0, 1}
 	}
 
-	// bind buffer before substituting data on it
-	shader.VertexBuffer.Bind(gl.ARRAY_BUFFER)
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*int(unsafe.Sizeof(ColorVertex{})), vertices)
+	// each write orphans the buffer, so it doesn't have to wait for the previous draw
+	shader.Stream.Write(vertices)
 	gl.DrawElements(gl.LINE_STRIP, len(indices), gl.UNSIGNED_INT, nil)
 
 	for i := float64(0); i < w; i++ {
@@ -121,8 +120,17 @@ func draw(app *App) {
 		}
 	}
 
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*int(unsafe.Sizeof(ColorVertex{})), vertices)
-	gl.DrawElements(gl.TRIANGLE_STRIP, len(indices), gl.UNSIGNED_INT, nil)
+	shader.Stream.Write(vertices)
+
+	// highlight one column of the grid by uploading just its vertices
+	column := int(time) % int(w)
+	first, last := column*int(h), (column+1)*int(h)
+	for i := first; i < last; i++ {
+		vertices[i].Color = mgl.Vec4{1, 1, 1, 0.8}
+	}
+	shader.Stream.UpdateRange(first, vertices[first:last])
+
+	gl.DrawElements(shader.Mode, shader.Count, gl.UNSIGNED_INT, nil)
 
 	shader.Unuse()
 }
@@ -35,11 +35,11 @@ const vertexShaderSource = `
 
 const fragmentShaderSource = `
 	#version 130
-		uniform sampler2D texture;
+		uniform sampler2D tex;
 		varying vec2 texCoord;
 
 		void main(void){
-			gl_FragColor = texture2D(texture, texCoord);
+			gl_FragColor = texture2D(tex, texCoord);
 		}
 `
 
@@ -108,8 +108,8 @@ func draw(app *App) {
 	shader.Projection.UniformMatrix4fv(false, projection)
 	shader.Model.UniformMatrix4fv(false, model)
 
-	// draw
-	gl.DrawArrays(gl.QUADS, 0, 4)
+	// draw, core profiles have no quads but a fan draws the same convex quad
+	gl.DrawArrays(gl.TRIANGLE_FAN, 0, 4)
 
 	shader.Unuse()
 }
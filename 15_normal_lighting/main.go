@@ -9,6 +9,8 @@ import (
 )
 
 var shader *Shader
+var lighting *Lighting
+var orbiting *Light
 var time float64
 
 const vertexShaderSource = `
@@ -18,31 +20,31 @@ const vertexShaderSource = `
 		in vec3 norm;
 
 		varying vec4 vertexColor;
+		varying vec3 worldPosition;
+		varying vec3 worldNormal;
 
 		uniform mat4 model;
 		uniform mat4 view;
 		uniform mat4 projection;
 		uniform mat3 normal;
 
-		vec4 doColor() {
-			vec3 normalized  = normalize(normal * normalize(norm));
-			vec3 light = normalize(vec3(1.0, 1.0, 1.0));
-			float df = max(dot(normalized, light), 0.0);
-			return vec4((color * df).xyz, 1.0);
-		}
-
 		void main()	{
-			vertexColor = doColor();
+			vertexColor = color;
+			worldPosition = (model * position).xyz;
+			worldNormal = normal * normalize(norm);
 			gl_Position = projection * view * model * position;
 		}
 `
 
 const fragmentShaderSource = `
 	#version 130
+` + LightingShaderSource + `
 		varying vec4 vertexColor;
+		varying vec3 worldPosition;
+		varying vec3 worldNormal;
 
 		void main() {
-			gl_FragColor = vertexColor;
+			gl_FragColor = vec4(applyLighting(worldPosition, worldNormal, vertexColor.rgb), vertexColor.a);
 		}
 `
 
@@ -114,7 +116,13 @@ func main() {
 		4, 0, 3, 7, // bottom
 	}
 
-	shader = NewNormalShader(&cube, indices, vertexShaderSource, fragmentShaderSource)
+	shader = NewNormalShaderWithMode(&cube, indices, gl.QUADS, vertexShaderSource, fragmentShaderSource)
+
+	// a dim sun, a point light circling the cube and a spot light from the camera
+	lighting = NewLighting(shader.Program)
+	lighting.Add(NewDirectionalLight(mgl.Vec3{-1, -1, -1}, mgl.Vec3{1, 1, 1}, 0.3))
+	orbiting = lighting.Add(NewPointLight(mgl.Vec3{0, 0, 3}, mgl.Vec3{1, 0.2, 0.2}, 1.5))
+	lighting.Add(NewSpotLight(mgl.Vec3{0, 0, 5}, mgl.Vec3{0, 0, -1}, mgl.Vec3{0.2, 0.4, 1}, 1, math.Pi/16, math.Pi/10))
 
 	app.Start()
 }
@@ -128,22 +136,25 @@ func draw(app *App) {
 	shader.Ortho.UniformMatrix4fv(false, ortho)
 
 	// view and projection
-	view := mgl.LookAtV(mgl.Vec3{0, 0, 5}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	camera := mgl.Vec3{0, 0, 5}
+	view := mgl.LookAtV(camera, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
 	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, -10.0)
 
 	// send view and projection to shader
 	shader.View.UniformMatrix4fv(false, view)
 	shader.Projection.UniformMatrix4fv(false, projection)
 
-	// transformation matrix for rotation
-	model := mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0})
-	shader.Model.UniformMatrix4fv(false, model)
+	// rotate the cube and send its model and world space normal matrix to shader
+	transform := NewTransform().Rotate(float32(time), mgl.Vec3{0, 1, 0})
+	shader.Model.UniformMatrix4fv(false, transform.Matrix())
+	shader.Normal.UniformMatrix3fv(false, transform.NormalMatrix())
 
-	// calculate normal matrix and send to shader
-	normal := view.Mul4(model).Mat3().Inv().Transpose()
-	shader.Normal.UniformMatrix3fv(false, normal)
+	// move the point light around and send all lights to shader
+	orbiting.Position = mgl.Vec3{float32(3 * math.Sin(time*3)), 1, float32(3 * math.Cos(time*3))}
+	lighting.Apply(camera)
 
-	gl.DrawElements(gl.QUADS, 24, gl.UNSIGNED_INT, nil)
+	// the quads were uploaded as triangles
+	gl.DrawElements(shader.Mode, shader.Count, gl.UNSIGNED_INT, nil)
 
 	shader.Unuse()
 }
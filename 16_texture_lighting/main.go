@@ -46,14 +46,14 @@ const vertexShaderSource = `
 
 const fragmentShaderSource = `
 	#version 130
-		uniform sampler2D texture;
+		uniform sampler2D tex;
    
 		varying vec2 texCoord;  
 		varying float diffuse;
 		varying vec4 inColor;
 
 		void main() {
-			gl_FragColor =  inColor * vec4(texture2D(texture, texCoord).rgb * diffuse, 1.0);
+			gl_FragColor =  inColor * vec4(texture2D(tex, texCoord).rgb * diffuse, 1.0);
 		}
 `
 
@@ -135,7 +135,7 @@ func main() {
 
 	texture := loadTexture("picture.png")
 
-	shader = NewNormalTexturedShader(&cube, indices, texture, vertexShaderSource, fragmentShaderSource)
+	shader = NewNormalTexturedShaderWithMode(&cube, indices, gl.QUADS, texture, vertexShaderSource, fragmentShaderSource)
 
 	app.Start()
 }
@@ -171,15 +171,13 @@ func draw(app *App) {
 	shader.View.UniformMatrix4fv(false, view)
 	shader.Projection.UniformMatrix4fv(false, projection)
 
-	// transformation matrix for rotation
-	model := mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0})
-	shader.Model.UniformMatrix4fv(false, model)
+	// rotate the cube and send its model and view space normal matrix to shader
+	transform := NewTransform().Rotate(float32(time), mgl.Vec3{0, 1, 0})
+	shader.Model.UniformMatrix4fv(false, transform.Matrix())
+	shader.Normal.UniformMatrix3fv(false, transform.ViewNormalMatrix(view))
 
-	// calculate normal matrix and send to shader
-	normal := view.Mul4(model).Mat3().Inv().Transpose()
-	shader.Normal.UniformMatrix3fv(false, normal)
-
-	gl.DrawElements(gl.QUADS, 24, gl.UNSIGNED_INT, nil)
+	// the quads were uploaded as triangles
+	gl.DrawElements(shader.Mode, shader.Count, gl.UNSIGNED_INT, nil)
 
 	shader.Unuse()
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+var title *Text
+var counter *Text
+var label *Text
+var time float64
+var frames int
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Text Example", draw)
+	defer app.Destroy()
+
+	font := NewFont(goregular.TTF, 24, 72, nil)
+
+	title = NewText(font, "Go GLFW3 Text Example\nTrueType glyphs from an atlas", AlignCenter)
+	title.Color = mgl.Vec4{1, 0.8, 0.2, 1}
+
+	counter = NewText(font, "", AlignRight)
+
+	label = NewText(font, "Hello, World!", AlignCenter)
+	label.Color = mgl.Vec4{0.4, 0.8, 1, 1}
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += 0.01
+	frames++
+
+	// view and projection
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 5}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, -10.0)
+
+	// billboarded label circling around the origin
+	position := mgl.Vec3{float32(2 * math.Cos(time)), 0, float32(2 * math.Sin(time))}
+	label.DrawBillboard(view, projection, position, 0.01)
+
+	// screen space text
+	title.Draw(app, (float32(app.Width)-title.Width)/2, 20)
+
+	counter.SetText(fmt.Sprintf("frame %d", frames))
+	counter.Draw(app, float32(app.Width)-counter.Width-20, float32(app.Height)-counter.Height-20)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var debug *DebugDraw
+var time float64
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Debug Draw Example", draw)
+	defer app.Destroy()
+
+	debug = NewDebugDraw()
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// view and projection
+	eye := mgl.Vec3{float32(6 * math.Sin(time)), 4, float32(6 * math.Cos(time))}
+	view := mgl.LookAtV(eye, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 100.0)
+
+	debug.Grid(mgl.Vec3{0, 0, 0}, 10, 10, mgl.Vec4{0.4, 0.4, 0.4, 1})
+	debug.Axes(mgl.Ident4(), 1)
+
+	model := mgl.HomogRotate3D(float32(time*2), mgl.Vec3{0, 1, 0})
+	debug.OrientedBox(model, mgl.Vec3{-1, 0, -1}, mgl.Vec3{1, 2, 1}, mgl.Vec4{1, 1, 0, 1})
+	debug.Sphere(mgl.Vec3{0, 1, 0}, 1.5, mgl.Vec4{0, 1, 1, 1})
+
+	// a small frustum floating above the scene
+	camera := mgl.Perspective(math.Pi/4.0, 1, 0.5, 2).Mul4(mgl.LookAtV(mgl.Vec3{0, 3, 3}, mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1, 0}))
+	debug.Frustum(camera, mgl.Vec4{1, 0, 1, 1})
+
+	// points stay for 60 frames, so the orbiting point leaves a trail whose tail just disappears
+	debug.DepthTest = false
+	debug.Lifetime = 60
+	debug.Point(mgl.Vec3{float32(3 * math.Cos(time*5)), 1, float32(3 * math.Sin(time*5))}, mgl.Vec4{1, 0.5, 0, 1})
+	debug.DepthTest = true
+	debug.Lifetime = 1
+
+	debug.Flush(view, projection)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var pictureMaterial *Material
+var checkerMaterial *Material
+var time float64
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+		in vec2 textureCoordinate;
+
+		varying vec2 texCoord;
+		varying vec4 inColor;
+
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			inColor = color;
+			texCoord = textureCoordinate;
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+		uniform sampler2D tex;
+		uniform vec4 tint;
+
+		varying vec2 texCoord;
+		varying vec4 inColor;
+
+		void main() {
+			gl_FragColor = tint * mix(inColor, texture2D(tex, texCoord), 0.75);
+		}
+`
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Materials Example", draw)
+	defer app.Destroy()
+
+	cube := NormalTextureVertices{
+		NormalTextureVertex{
+			Position:          mgl.Vec4{1, -1, 1, 1},
+			Color:             mgl.Vec4{1, 1, 0, 1},
+			Normal:            mgl.Vec3{1, -1, 1},
+			TextureCoordinate: mgl.Vec2{1, 1},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{1, 1, 1, 1},
+			Color:             mgl.Vec4{0, 1, 0, 1},
+			Normal:            mgl.Vec3{1, 1, 1},
+			TextureCoordinate: mgl.Vec2{1, 0},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{-1, 1, 1, 1},
+			Color:             mgl.Vec4{1, 1, 0, 1},
+			Normal:            mgl.Vec3{-1, 1, 1},
+			TextureCoordinate: mgl.Vec2{0, 0},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{-1, -1, 1, 1},
+			Color:             mgl.Vec4{1, 0, 0, 1},
+			Normal:            mgl.Vec3{-1, -1, 1},
+			TextureCoordinate: mgl.Vec2{0, 1},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{1, -1, -1, 1},
+			Color:             mgl.Vec4{0, 1, 0, 1},
+			Normal:            mgl.Vec3{1, -1, -1},
+			TextureCoordinate: mgl.Vec2{0, 1},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{1, 1, -1, 1},
+			Color:             mgl.Vec4{0, 0, 1, 1},
+			Normal:            mgl.Vec3{1, 1, -1},
+			TextureCoordinate: mgl.Vec2{0, 0},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{-1, 1, -1, 1},
+			Color:             mgl.Vec4{1, 0, 0, 1},
+			Normal:            mgl.Vec3{-1, 1, -1},
+			TextureCoordinate: mgl.Vec2{1, 0},
+		},
+		NormalTextureVertex{
+			Position:          mgl.Vec4{-1, -1, -1, 1},
+			Color:             mgl.Vec4{0, 0, 1, 1},
+			Normal:            mgl.Vec3{-1, -1, -1},
+			TextureCoordinate: mgl.Vec2{1, 1},
+		},
+	}
+	/*
+	       //6-------------/5
+	     //  .           // |
+	   //2--------------1   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    7.......   |   /4
+	   //               | //
+	   //3--------------/0
+	*/
+
+	indices := []int32{
+		0, 1, 2, 3, // front
+		7, 6, 5, 4, // back
+		3, 2, 6, 7, // left
+		4, 5, 1, 0, // right
+		1, 5, 6, 2, // top
+		4, 0, 3, 7, // bottom
+	}
+
+	// one mesh, drawn twice with two different materials
+	mesh = NewMesh(cube, indices, gl.QUADS)
+	// ranges count the quad indices, 4 per face
+	mesh.AddSubMesh("caps", 0, 8)
+	mesh.AddSubMesh("sides", 8, 16)
+
+	pictureMaterial = NewMaterial(vertexShaderSource, fragmentShaderSource)
+	pictureMaterial.SetTexture("tex", NewImageTexture(loadTexture("picture.png")))
+	pictureMaterial.Set("tint", mgl.Vec4{1, 1, 1, 1})
+
+	// clone shares the program, only the texture and tint differ
+	checkerMaterial = pictureMaterial.Clone()
+	checkerMaterial.SetTexture("tex", NewTexture(8, 8, checker(8, 8)))
+	checkerMaterial.Set("tint", mgl.Vec4{1, 0.6, 0.6, 1})
+	// render state is part of the material, the App's state is restored after each draw
+	checkerMaterial.State.PolygonMode = gl.LINE
+	app.RenderState.ClearColor = mgl.Vec4{0.15, 0.15, 0.2, 1}
+
+	app.Start()
+
+	// the clone shares the program, deleting one material frees it for both,
+	// textures aren't owned by materials
+	mesh.Delete()
+	pictureMaterial.Delete()
+	for _, material := range []*Material{pictureMaterial, checkerMaterial} {
+		for _, binding := range material.Textures {
+			Resources.Release(binding.Texture)
+		}
+	}
+}
+
+func loadTexture(filename string) *image.NRGBA {
+	texfile, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer texfile.Close()
+
+	img, err := png.Decode(texfile)
+	if err != nil {
+		panic(err)
+	}
+
+	return img.(*image.NRGBA)
+}
+
+func checker(w, h int) *[]mgl.Vec4 {
+	var data []mgl.Vec4
+	for i := 0; i < w; i++ {
+		for j := 0; j < h; j++ {
+			if (i+j)%2 == 0 {
+				data = append(data, mgl.Vec4{1, 1, 1, 1})
+			} else {
+				data = append(data, mgl.Vec4{0.2, 0.2, 0.2, 1})
+			}
+		}
+	}
+	return &data
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// view and projection
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 8}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, -10.0)
+
+	// transformation matrix for rotation, each cube on its own side
+	rotation := mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0})
+	left := mgl.Translate3D(-2, 0, 0).Mul4(rotation)
+	right := mgl.Translate3D(2, 0, 0).Mul4(rotation)
+
+	// left cube as a whole, right cube with a different material for its caps and sides
+	drawMesh(pictureMaterial, left, view, projection, "")
+	drawMesh(pictureMaterial, right, view, projection, "caps")
+	drawMesh(checkerMaterial, right, view, projection, "sides")
+
+	app.Window.SetTitle(fmt.Sprintf("Go GLFW3 Materials Example - %d GL calls, %d skipped", State.LastFrameCalls, State.LastFrameSaved))
+}
+
+func drawMesh(material *Material, model, view, projection mgl.Mat4, subMesh string) {
+	material.Set("view", view)
+	material.Set("projection", projection)
+	material.Set("model", model)
+
+	material.Use()
+	var err error
+	if subMesh == "" {
+		err = mesh.Draw(material)
+	} else {
+		err = mesh.DrawSubMesh(material, subMesh)
+	}
+	if err != nil {
+		panic(err)
+	}
+	material.Unuse()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var box *Mesh
+var material *Material
+var lighting *Lighting
+var sun *Light
+var spot *Light
+var shadow *ShadowMap
+var time float64
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+		in vec3 norm;
+
+		varying vec4 vertexColor;
+		varying vec3 worldPosition;
+		varying vec3 worldNormal;
+
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+		uniform mat3 normal;
+
+		void main()	{
+			vertexColor = color;
+			worldPosition = (model * position).xyz;
+			worldNormal = normal * normalize(norm);
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+` + LightingShaderSource + `
+		varying vec4 vertexColor;
+		varying vec3 worldPosition;
+		varying vec3 worldNormal;
+
+		uniform vec4 tint;
+
+		void main() {
+			vec4 albedo = vertexColor * tint;
+			gl_FragColor = vec4(applyLighting(worldPosition, worldNormal, albedo.rgb), albedo.a);
+		}
+`
+
+// scene bounds, used to fit the shadow map
+var sceneMin = mgl.Vec3{-6, -1, -6}
+var sceneMax = mgl.Vec3{6, 3, 6}
+
+type object struct {
+	model mgl.Mat4
+	color mgl.Vec4
+}
+
+var objects []object
+
+func main() {
+	app := NewApp(640, 480, "Go GLFW3 Shadow Mapping Example", UpdateViewport, draw, onKeyDown, OnMouseDown, OnMouseMove, OnError)
+	defer app.Destroy()
+
+	box = NewMesh(cube(), cubeIndices(), gl.QUADS)
+	material = NewMaterial(vertexShaderSource, fragmentShaderSource)
+
+	lighting = NewLighting(material.Program)
+	sun = lighting.Add(NewDirectionalLight(mgl.Vec3{-1, -2, -1}, mgl.Vec3{1, 1, 0.9}, 0.8))
+	spot = NewSpotLight(mgl.Vec3{0, 6, 3}, mgl.Vec3{0, -2, -1}, mgl.Vec3{1, 0.8, 0.6}, 2, math.Pi/8, math.Pi/5)
+
+	shadow = NewShadowMap(sun, 2048)
+	shadow.PCF = 2
+	lighting.Shadow = shadow
+
+	// floor and a few boxes standing on it
+	objects = append(objects, object{mgl.Translate3D(0, -1, 0).Mul4(mgl.Scale3D(6, 0.1, 6)), mgl.Vec4{0.8, 0.8, 0.8, 1}})
+	for i := 0; i < 5; i++ {
+		angle := float64(i) * 2 * math.Pi / 5
+		model := mgl.Translate3D(float32(3*math.Cos(angle)), 0, float32(3*math.Sin(angle))).Mul4(mgl.Scale3D(0.5, 1, 0.5))
+		objects = append(objects, object{model, mgl.Vec4{float32(i) / 5, 0.5, 1 - float32(i)/5, 1}})
+	}
+	objects = append(objects, object{mgl.Ident4(), mgl.Vec4{1, 0.3, 0.3, 1}})
+
+	app.Start()
+}
+
+// switch the shadow casting light between sun and spot light
+func onKeyDown(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
+	OnKeyDown(window, key, scancode, action, mod)
+
+	if key == glfw.KeySpace && action == glfw.Press {
+		if shadow.Light == sun {
+			lighting.Lights = []*Light{spot}
+			shadow.Light = spot
+		} else {
+			lighting.Lights = []*Light{sun}
+			shadow.Light = sun
+		}
+	}
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// spin the center box
+	objects[len(objects)-1].model = mgl.Translate3D(0, 0.5, 0).Mul4(mgl.HomogRotate3D(float32(time), mgl.Vec3{1, 1, 0}.Normalize()))
+
+	// render depth from the light
+	shadow.Fit(sceneMin, sceneMax)
+	shadow.Begin()
+	for _, o := range objects {
+		if err := shadow.DrawMesh(box, o.model); err != nil {
+			panic(err)
+		}
+	}
+	shadow.End(app)
+
+	// view and projection
+	camera := mgl.Vec3{float32(10 * math.Sin(time/4)), 6, float32(10 * math.Cos(time/4))}
+	view := mgl.LookAtV(camera, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 100.0)
+
+	material.Use()
+	material.SetUniform("view", view)
+	material.SetUniform("projection", projection)
+	lighting.Apply(camera)
+
+	for _, o := range objects {
+		material.SetUniform("model", o.model)
+		material.SetUniform("normal", o.model.Mat3().Inv().Transpose())
+		material.SetUniform("tint", o.color)
+		if err := box.Draw(material); err != nil {
+			panic(err)
+		}
+	}
+
+	material.Unuse()
+}
+
+// unit cube with flat face normals, 4 vertices per face
+func cube() NormalVertices {
+	faces := []struct {
+		normal mgl.Vec3
+		u, v   mgl.Vec3
+	}{
+		{mgl.Vec3{0, 0, 1}, mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 1, 0}},
+		{mgl.Vec3{0, 0, -1}, mgl.Vec3{-1, 0, 0}, mgl.Vec3{0, 1, 0}},
+		{mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 0, -1}, mgl.Vec3{0, 1, 0}},
+		{mgl.Vec3{-1, 0, 0}, mgl.Vec3{0, 0, 1}, mgl.Vec3{0, 1, 0}},
+		{mgl.Vec3{0, 1, 0}, mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 0, -1}},
+		{mgl.Vec3{0, -1, 0}, mgl.Vec3{1, 0, 0}, mgl.Vec3{0, 0, 1}},
+	}
+
+	var vertices NormalVertices
+	for _, f := range faces {
+		for _, c := range [][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			p := f.normal.Add(f.u.Mul(c[0])).Add(f.v.Mul(c[1]))
+			vertices = append(vertices, NormalVertex{
+				Position: p.Vec4(1),
+				Color:    mgl.Vec4{1, 1, 1, 1},
+				Normal:   f.normal,
+			})
+		}
+	}
+	return vertices
+}
+
+func cubeIndices() []int32 {
+	indices := make([]int32, 24)
+	for i := range indices {
+		indices[i] = int32(i)
+	}
+	return indices
+}
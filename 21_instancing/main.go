@@ -0,0 +1,144 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var material *Material
+var instanceBuffer *InstanceBuffer
+var instances Instances
+var time float64
+
+const n = 10
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+		in mat4 instanceModel;
+		in vec4 instanceColor;
+
+		varying vec4 vertexColor;
+
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			vertexColor = color * instanceColor;
+			gl_Position = projection * view * instanceModel * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vertexColor;
+		}
+`
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Instancing Example", draw)
+	defer app.Destroy()
+
+	cube := ColorVertices{
+		ColorVertex{
+			Position: mgl.Vec4{1, -1, 1, 1},
+			Color:    mgl.Vec4{1, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, 1, 1, 1},
+			Color:    mgl.Vec4{0, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, 1, 1, 1},
+			Color:    mgl.Vec4{1, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, -1, 1, 1},
+			Color:    mgl.Vec4{1, 0, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, -1, -1, 1},
+			Color:    mgl.Vec4{0, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, 1, -1, 1},
+			Color:    mgl.Vec4{0, 0, 1, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, 1, -1, 1},
+			Color:    mgl.Vec4{1, 0, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, -1, -1, 1},
+			Color:    mgl.Vec4{0, 0, 1, 1},
+		},
+	}
+	/*
+	       //6-------------/5
+	     //  .           // |
+	   //2--------------1   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    7.......   |   /4
+	   //               | //
+	   //3--------------/0
+	*/
+
+	indices := []int32{
+		0, 1, 2, 3, // front
+		7, 6, 5, 4, // back
+		3, 2, 6, 7, // left
+		4, 5, 1, 0, // right
+		1, 5, 6, 2, // top
+		4, 0, 3, 7, // bottom
+	}
+
+	mesh = NewMesh(cube, indices, gl.QUADS)
+	material = NewMaterial(vertexShaderSource, fragmentShaderSource)
+
+	// one instance per cube in a n*n*n grid
+	instances = make(Instances, n*n*n)
+	for i := range instances {
+		x, y, z := i%n, (i/n)%n, i/(n*n)
+		instances[i].Color = mgl.Vec4{float32(x) / n, float32(y) / n, float32(z) / n, 1}
+	}
+	instanceBuffer = NewInstanceBuffer(instances)
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// update all model matrices and upload them in one go
+	for i := range instances {
+		x, y, z := i%n, (i/n)%n, i/(n*n)
+		position := mgl.Vec3{float32(x-n/2) * 3, float32(y-n/2) * 3, float32(z-n/2) * 3}
+		rotation := mgl.HomogRotate3D(float32(time)+float32(i)*0.1, mgl.Vec3{0, 1, 0})
+		instances[i].Model = mgl.Translate3D(position[0], position[1], position[2]).Mul4(rotation).Mul4(mgl.Scale3D(0.5, 0.5, 0.5))
+	}
+	instanceBuffer.Update(instances)
+
+	// view and projection
+	view := mgl.LookAtV(mgl.Vec3{float32(40 * math.Sin(time/4)), 20, float32(40 * math.Cos(time/4))}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 200.0)
+
+	material.Set("view", view)
+	material.Set("projection", projection)
+
+	material.Use()
+	if err := mesh.DrawInstanced(material, instanceBuffer); err != nil {
+		panic(err)
+	}
+	material.Unuse()
+}
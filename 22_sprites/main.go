@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"math/rand"
+	"os"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var batch *SpriteBatch
+var sprites []Sprite
+var velocities []mgl.Vec2
+var time float64
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Sprite Batch Example", draw)
+	defer app.Destroy()
+
+	textures := []gl.Texture{
+		NewImageTexture(loadTexture("picture.png")),
+		NewTexture(2, 2, &[]mgl.Vec4{{1, 1, 1, 1}, {0.5, 0.5, 1, 1}, {0.5, 0.5, 1, 1}, {1, 1, 1, 1}}),
+		NewTexture(1, 1, &[]mgl.Vec4{{1, 1, 1, 1}}),
+	}
+
+	batch = NewSpriteBatch(256)
+	batch.Sort = SortByDepth
+
+	for i := 0; i < 300; i++ {
+		size := 16 + rand.Float32()*48
+		sprite := NewSprite(textures[i%len(textures)], mgl.Vec2{rand.Float32() * 640, rand.Float32() * 480}, mgl.Vec2{size, size})
+		sprite.Tint = mgl.Vec4{0.5 + rand.Float32()/2, 0.5 + rand.Float32()/2, 0.5 + rand.Float32()/2, 0.8}
+		// a few layers, so sprites on the same layer can still be batched by texture
+		sprite.Depth = float32(rand.Intn(3)) / 3
+		sprites = append(sprites, sprite)
+		velocities = append(velocities, mgl.Vec2{rand.Float32()*4 - 2, rand.Float32()*4 - 2})
+	}
+
+	app.Start()
+}
+
+func loadTexture(filename string) *image.NRGBA {
+	texfile, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer texfile.Close()
+
+	img, err := png.Decode(texfile)
+	if err != nil {
+		panic(err)
+	}
+
+	return img.(*image.NRGBA)
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	batch.Begin(app)
+	for i := range sprites {
+		// bounce off the window borders
+		p := sprites[i].Position.Add(velocities[i])
+		if p[0] < 0 || p[0] > float32(app.Width) {
+			velocities[i][0] = -velocities[i][0]
+		}
+		if p[1] < 0 || p[1] > float32(app.Height) {
+			velocities[i][1] = -velocities[i][1]
+		}
+		sprites[i].Position = p
+		sprites[i].Rotation = float32(math.Sin(time + float64(i)))
+
+		batch.Draw(sprites[i])
+	}
+	batch.End()
+
+	app.Window.SetTitle(fmt.Sprintf("Go GLFW3 Sprite Batch Example - %d sprites, %d draw calls", len(sprites), batch.DrawCalls))
+}
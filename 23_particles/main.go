@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	glfw "github.com/go-gl/glfw3"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var renderer *ParticleRenderer
+var debug *DebugDraw
+var fountain *Emitter
+var smoke *Emitter
+var sparks *Emitter
+var time float64
+
+func main() {
+	app := NewApp(640, 480, "Go GLFW3 Particles Example", UpdateViewport, draw, onKeyDown, OnMouseDown, OnMouseMove, OnError)
+	defer app.Destroy()
+
+	renderer = NewParticleRenderer(0)
+	renderer.Additive = true
+	debug = NewDebugDraw()
+
+	// water like fountain shooting upwards
+	fountain = NewEmitter(mgl.Vec3{-2, 0, 0}, ConeShape{Direction: mgl.Vec3{0, 1, 0}, Angle: math.Pi / 12, Radius: 0.1}, 200)
+	fountain.Speed, fountain.SpeedVariance = 6, 1
+	fountain.Lifetime = 1.5
+	fountain.AddAffector(Gravity{mgl.Vec3{0, -9.81, 0}})
+	fountain.AddAffector(ColorOverLifetime{mgl.Vec4{0.3, 0.6, 1, 1}, mgl.Vec4{0.1, 0.1, 0.5, 0}})
+
+	// smoke swirling up from a box
+	smoke = NewEmitter(mgl.Vec3{2, 0, 0}, BoxShape{mgl.Vec3{-0.5, 0, -0.5}, mgl.Vec3{0.5, 0.1, 0.5}}, 60)
+	smoke.Speed = 0.3
+	smoke.Lifetime, smoke.LifetimeVariance = 4, 1
+	smoke.AddAffector(Gravity{mgl.Vec3{0, 0.8, 0}})
+	smoke.AddAffector(Drag{0.5})
+	smoke.AddAffector(Vortex{Center: mgl.Vec3{2, 0, 0}, Axis: mgl.Vec3{0, 1, 0}, Strength: 2})
+	smoke.AddAffector(SizeOverLifetime{0.2, 1})
+	smoke.AddAffector(ColorOverLifetime{mgl.Vec4{0.5, 0.5, 0.5, 0.5}, mgl.Vec4{0.2, 0.2, 0.2, 0}})
+
+	// sparks only on key press
+	sparks = NewEmitter(mgl.Vec3{0, 2, 0}, SphereShape{0.2}, 0)
+	sparks.Speed, sparks.SpeedVariance = 3, 1
+	sparks.Lifetime = 1
+	sparks.Size = 0.15
+	sparks.AddAffector(Drag{2})
+	sparks.AddAffector(ColorOverLifetime{mgl.Vec4{1, 0.9, 0.3, 1}, mgl.Vec4{1, 0.2, 0, 0}})
+
+	app.Start()
+}
+
+func onKeyDown(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
+	OnKeyDown(window, key, scancode, action, mod)
+
+	if key == glfw.KeySpace && action == glfw.Press {
+		sparks.Burst(200)
+	}
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// simulate with a fixed timestep
+	for _, e := range []*Emitter{fountain, smoke, sparks} {
+		e.Update(1.0 / 60.0)
+	}
+
+	// view and projection
+	view := mgl.LookAtV(mgl.Vec3{float32(8 * math.Sin(time/2)), 3, float32(8 * math.Cos(time/2))}, mgl.Vec3{0, 1, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 100.0)
+
+	debug.Grid(mgl.Vec3{0, 0, 0}, 10, 10, mgl.Vec4{0.4, 0.4, 0.4, 1})
+	debug.Flush(view, projection)
+
+	renderer.Draw(view, projection, fountain, smoke, sparks)
+}
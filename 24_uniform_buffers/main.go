@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var materials []*Material
+var frame *UniformBuffer
+var frameData FrameUniforms
+var time float64
+
+const vertexShaderSource = `
+	#version 130
+` + FrameUniformsShaderSource + `
+		in vec4 position;
+		in vec4 color;
+
+		varying vec4 vertexColor;
+
+		uniform mat4 model;
+
+		void main()	{
+			vertexColor = color;
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const colorFragmentShaderSource = `
+	#version 130
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vertexColor;
+		}
+`
+
+const pulseFragmentShaderSource = `
+	#version 130
+` + FrameUniformsShaderSource + `
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vec4(vec3(1.0) - vertexColor.rgb * (0.5 + 0.5 * sin(time * 4.0)), 1.0);
+		}
+`
+
+func main() {
+	// the shaders are written for GLSL 1.30 and get adapted to the 3.30 core profile
+	WindowSettings.Core = true
+	app := NewSimpleApp(640, 480, "Go GLFW3 Uniform Buffer Example", draw)
+	defer app.Destroy()
+
+	cube := ColorVertices{
+		ColorVertex{
+			Position: mgl.Vec4{1, -1, 1, 1},
+			Color:    mgl.Vec4{1, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, 1, 1, 1},
+			Color:    mgl.Vec4{0, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, 1, 1, 1},
+			Color:    mgl.Vec4{1, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, -1, 1, 1},
+			Color:    mgl.Vec4{1, 0, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, -1, -1, 1},
+			Color:    mgl.Vec4{0, 1, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{1, 1, -1, 1},
+			Color:    mgl.Vec4{0, 0, 1, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, 1, -1, 1},
+			Color:    mgl.Vec4{1, 0, 0, 1},
+		},
+		ColorVertex{
+			Position: mgl.Vec4{-1, -1, -1, 1},
+			Color:    mgl.Vec4{0, 0, 1, 1},
+		},
+	}
+	/*
+	       //6-------------/5
+	     //  .           // |
+	   //2--------------1   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    .          |   |
+	   //    7.......   |   /4
+	   //               | //
+	   //3--------------/0
+	*/
+
+	indices := []int32{
+		0, 1, 2, 3, // front
+		7, 6, 5, 4, // back
+		3, 2, 6, 7, // left
+		4, 5, 1, 0, // right
+		1, 5, 6, 2, // top
+		4, 0, 3, 7, // bottom
+	}
+
+	mesh = NewMesh(cube, indices, gl.QUADS)
+
+	// both programs read view and projection from the same uniform buffer
+	frame = NewUniformBuffer(&frameData, FrameBinding)
+	for _, source := range []string{colorFragmentShaderSource, pulseFragmentShaderSource} {
+		material := NewMaterial(vertexShaderSource, source)
+		frame.BindProgram(material.Program, "Frame")
+		materials = append(materials, material)
+	}
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	// update the shared per frame data once
+	frameData.CameraPosition = mgl.Vec3{0, 0, 8}
+	frameData.View = mgl.LookAtV(frameData.CameraPosition, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	frameData.Projection = mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 100.0)
+	frameData.Time = float32(time)
+	frame.Update(&frameData)
+
+	for i, material := range materials {
+		model := mgl.Translate3D(float32(i*4-2), 0, 0).Mul4(mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0}))
+
+		material.Use()
+		material.SetUniform("model", model)
+		if err := mesh.Draw(material); err != nil {
+			panic(err)
+		}
+		material.Unuse()
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var material *Material
+var angle float32
+var spinning = true
+var closeRequested = -10.0
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec2 textureCoordinate;
+
+		varying vec2 texCoord;
+
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			texCoord = textureCoordinate;
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+		uniform sampler2D tex;
+		varying vec2 texCoord;
+
+		void main(void){
+			gl_FragColor = texture2D(tex, texCoord);
+		}
+`
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Multiple Windows Example", drawMain)
+	defer app.Destroy()
+
+	// the tool window shares the texture, buffers and program of the main window
+	tool := NewSharedApp(app, 320, 240, "Tool Window", UpdateViewport, drawTool, onToolKey, OnMouseDown, OnMouseMove)
+	defer tool.Destroy()
+
+	// closing the main window closes both, so it has to be confirmed within two seconds
+	app.OnClose(func(app *App) bool {
+		if app.Time-closeRequested < 2 {
+			return true
+		}
+		closeRequested = app.Time
+		log.Printf("close again to quit\n")
+		return false
+	})
+	tool.OnResize(func(app *App, width, height int) {
+		log.Printf("tool window resized to %dx%d\n", width, height)
+	})
+
+	slab := TextureVertices{
+		TextureVertex{
+			Position:          mgl.Vec4{-1, -1, 0, 1},
+			TextureCoordinate: mgl.Vec2{0, 0},
+		},
+		TextureVertex{
+			Position:          mgl.Vec4{-1, 1, 0, 1},
+			TextureCoordinate: mgl.Vec2{0, 1},
+		},
+		TextureVertex{
+			Position:          mgl.Vec4{1, 1, 0, 1},
+			TextureCoordinate: mgl.Vec2{1, 1},
+		},
+		TextureVertex{
+			Position:          mgl.Vec4{1, -1, 0, 1},
+			TextureCoordinate: mgl.Vec2{1, 0},
+		},
+	}
+
+	var w float32 = 8
+	var h float32 = 8
+	var data []mgl.Vec4
+	var checkered bool
+
+	for i := float32(0); i < w; i++ {
+		for j := float32(0); j < h; j++ {
+			if checkered {
+				data = append(data, mgl.Vec4{1, 0.5, 0, 1})
+			} else {
+				data = append(data, mgl.Vec4{0, 0.5, 1, 1})
+			}
+			checkered = !checkered
+		}
+		checkered = !checkered
+	}
+
+	mesh = NewMesh(slab, []uint8{0, 1, 2, 3}, gl.QUADS)
+	material = NewMaterial(vertexShaderSource, fragmentShaderSource)
+	material.SetTexture("tex", NewTexture(int(w), int(h), &data))
+
+	// drives both windows until the main one gets closed
+	app.Start()
+}
+
+// the mesh keeps a vertex array object per window, buffers, program and texture are shared
+func drawSlab(app *App, view, model mgl.Mat4) {
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 10.0)
+	material.Set("view", view)
+	material.Set("projection", projection)
+	material.Set("model", model)
+
+	material.Use()
+	if err := mesh.Draw(material); err != nil {
+		panic(err)
+	}
+	material.Unuse()
+}
+
+func drawMain(app *App) {
+	if spinning {
+		angle += float32(app.Delta)
+	}
+
+	view := mgl.LookAtV(mgl.Vec3{0, 1, 3}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	drawSlab(app, view, mgl.HomogRotate3D(angle, mgl.Vec3{0, 1, 0}))
+}
+
+// the tool window shows the slab without rotation
+func drawTool(app *App) {
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 2.5}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	drawSlab(app, view, mgl.Ident4())
+}
+
+func onToolKey(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
+	OnKeyDown(window, key, scancode, action, mod)
+
+	// space in the tool window pauses the main window
+	if key == glfw.KeySpace && action == glfw.Press {
+		spinning = !spinning
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var material *Material
+var debug *DebugDraw
+var corners []mgl.Vec3
+var time float64
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+
+		varying vec4 vertexColor;
+
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			vertexColor = color;
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vertexColor;
+		}
+`
+
+func main() {
+	app := NewSimpleApp(640, 480, "Go GLFW3 Picking Example", draw)
+	defer app.Destroy()
+
+	cube := ColorVertices{
+		ColorVertex{Position: mgl.Vec4{1, -1, 1, 1}, Color: mgl.Vec4{1, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, 1, 1, 1}, Color: mgl.Vec4{0, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, 1, 1, 1}, Color: mgl.Vec4{1, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, -1, 1, 1}, Color: mgl.Vec4{1, 0, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, -1, -1, 1}, Color: mgl.Vec4{0, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, 1, -1, 1}, Color: mgl.Vec4{0, 0, 1, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, 1, -1, 1}, Color: mgl.Vec4{1, 0, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, -1, -1, 1}, Color: mgl.Vec4{0, 0, 1, 1}},
+	}
+	indices := []uint8{
+		0, 1, 2, 3, // front
+		7, 6, 5, 4, // back
+		3, 2, 6, 7, // left
+		4, 5, 1, 0, // right
+		1, 5, 6, 2, // top
+		4, 0, 3, 7, // bottom
+	}
+
+	mesh = NewMesh(cube, indices, gl.QUADS)
+	corners = PositionsOf(cube)
+	material = NewMaterial(vertexShaderSource, fragmentShaderSource)
+	debug = NewDebugDraw()
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += 0.01
+
+	view := mgl.LookAtV(mgl.Vec3{0, 3, 8}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 100.0)
+	material.Set("view", view)
+	material.Set("projection", projection)
+
+	rotation := mgl.HomogRotate3D(float32(time), mgl.Vec3{0, 1, 0})
+	models := []mgl.Mat4{
+		mgl.Translate3D(-2, 0, 0).Mul4(rotation),
+		mgl.Translate3D(2, 0, 0).Mul4(rotation).Mul4(mgl.Scale3D(0.75, 1.5, 0.75)),
+	}
+
+	// the closest hit of all cubes under the cursor
+	ray := app.CursorRay(view, projection)
+	var closest Hit
+	picked := -1
+	for i, model := range models {
+		material.Set("model", model)
+		material.Use()
+		if err := mesh.Draw(material); err != nil {
+			panic(err)
+		}
+		material.Unuse()
+
+		if hit, ok := mesh.Pick(ray, model); ok && (picked < 0 || hit.Distance < closest.Distance) {
+			closest = hit
+			picked = i
+		}
+	}
+
+	title := "Go GLFW3 Picking Example"
+	if picked >= 0 {
+		// outline the triangle under the cursor
+		var points []mgl.Vec3
+		for _, vertex := range closest.Vertices {
+			p := models[picked].Mul4x1(corners[vertex].Vec4(1))
+			points = append(points, mgl.Vec3{p[0], p[1], p[2]})
+		}
+		debug.DepthTest = false
+		debug.Lines([]mgl.Vec3{points[0], points[1], points[1], points[2], points[2], points[0]}, mgl.Vec4{1, 1, 1, 1})
+		debug.Point(closest.Point, mgl.Vec4{1, 0, 1, 1})
+		debug.DepthTest = true
+
+		title = fmt.Sprintf("%v - cube %d, triangle %d, distance %.2f, barycentric %.2f", title, picked, closest.Triangle, closest.Distance, closest.Barycentric)
+	}
+	app.Window.SetTitle(title)
+
+	debug.Flush(view, projection)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/JamesClonk/opengl/_includes"
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+var mesh *Mesh
+var material *Material
+var models []mgl.Mat4
+var time float64
+
+const vertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+
+		varying vec4 vertexColor;
+
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			vertexColor = color;
+			gl_Position = projection * view * model * position;
+		}
+`
+
+const fragmentShaderSource = `
+	#version 130
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vertexColor;
+		}
+`
+
+func main() {
+	app := NewApp(640, 480, "Go GLFW3 Frustum Culling Example", UpdateViewport, draw, onKeyDown, OnMouseDown, OnMouseMove, OnError)
+	defer app.Destroy()
+
+	cube := ColorVertices{
+		ColorVertex{Position: mgl.Vec4{1, -1, 1, 1}, Color: mgl.Vec4{1, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, 1, 1, 1}, Color: mgl.Vec4{0, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, 1, 1, 1}, Color: mgl.Vec4{1, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, -1, 1, 1}, Color: mgl.Vec4{1, 0, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, -1, -1, 1}, Color: mgl.Vec4{0, 1, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{1, 1, -1, 1}, Color: mgl.Vec4{0, 0, 1, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, 1, -1, 1}, Color: mgl.Vec4{1, 0, 0, 1}},
+		ColorVertex{Position: mgl.Vec4{-1, -1, -1, 1}, Color: mgl.Vec4{0, 0, 1, 1}},
+	}
+	indices := []uint8{
+		0, 1, 2, 3, // front
+		7, 6, 5, 4, // back
+		3, 2, 6, 7, // left
+		4, 5, 1, 0, // right
+		1, 5, 6, 2, // top
+		4, 0, 3, 7, // bottom
+	}
+
+	mesh = NewMesh(cube, indices, gl.QUADS)
+	material = NewMaterial(vertexShaderSource, fragmentShaderSource)
+
+	// a field of 32x32 cubes around the camera
+	for x := -16; x < 16; x++ {
+		for z := -16; z < 16; z++ {
+			model := mgl.Translate3D(float32(x)*4, 0, float32(z)*4).Mul4(mgl.Scale3D(0.5, 0.5, 0.5))
+			models = append(models, model)
+		}
+	}
+
+	app.Start()
+}
+
+func draw(app *App) {
+	time += app.Delta * 0.3
+
+	eye := mgl.Vec3{0, 3, 0}
+	target := mgl.Vec3{float32(math.Sin(time)), 2.5, float32(math.Cos(time))}
+	view := mgl.LookAtV(eye, target, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/3.0, app.Ratio, 0.1, 50.0)
+
+	material.Set("view", view)
+	material.Set("projection", projection)
+	material.Use()
+
+	app.Culling.SetViewProjection(view, projection)
+	for _, model := range models {
+		// cubes behind the camera or beyond the far plane are never drawn
+		if err := mesh.DrawCulled(material, model); err != nil {
+			panic(err)
+		}
+	}
+	material.Unuse()
+
+	app.Window.SetTitle(fmt.Sprintf("Go GLFW3 Frustum Culling Example - %d of %d culled (C toggles culling)", app.Culling.LastFrameCulled, app.Culling.LastFrameTested))
+}
+
+func onKeyDown(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
+	OnKeyDown(window, key, scancode, action, mod)
+
+	if key == glfw.KeyC && action == glfw.Press {
+		culling := AppOf(window).Culling
+		culling.Enabled = !culling.Enabled
+	}
+}
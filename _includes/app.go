@@ -3,6 +3,7 @@ package _includes
 import (
 	"log"
 	"runtime"
+	"sort"
 
 	"github.com/go-gl/gl"
 	glfw "github.com/go-gl/glfw3"
@@ -21,6 +22,39 @@ type App struct {
 	MouseFunc    func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey)
 	CursorFunc   func(*glfw.Window, float64, float64)
 	ErrorFunc    func(glfw.ErrorCode, string)
+	Config       WindowConfig
+	// frustum culling for the draws of this App, counters are kept per frame
+	Culling *Culler
+	// state every frame starts with and gets cleared by, draws with their own state return to it
+	RenderState RenderState
+	// GL debug output is active, see DebugOutput
+	Debugging bool
+	// seconds since Start and since the previous frame, simulated while recording
+	Time       float64
+	Delta      float64
+	Recorder   *Recorder
+	screenshot bool
+	// position and size to return to from fullscreen
+	windowed         [4]int
+	toggleFullscreen bool
+	// id of the window's context and the State used while it is current
+	context int
+	state   *StateCache
+	// closed while another App runs the event loop
+	hidden bool
+	// window state tracked through events, see OnFocus, OnIconify and OnContentScale
+	Focused   bool
+	Iconified bool
+	ScaleX    float32
+	ScaleY    float32
+	events    appEvents
+}
+
+// callbacks only get the window, this finds the App it belongs to
+var apps = make(map[*glfw.Window]*App)
+
+func AppOf(window *glfw.Window) *App {
+	return apps[window]
 }
 
 func NewSimpleApp(width, height int, title string, drawFunc func(*App)) *App {
@@ -28,37 +62,48 @@ func NewSimpleApp(width, height int, title string, drawFunc func(*App)) *App {
 }
 
 func NewApp(width, height int, title string, viewportFunc func(*App), drawFunc func(*App), keyFunc func(*glfw.Window, glfw.Key, int, glfw.Action, glfw.ModifierKey), mouseFunc func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey), cursorFunc func(*glfw.Window, float64, float64), errorFunc func(glfw.ErrorCode, string)) *App {
-	runtime.LockOSThread()
+	return newApp(nil, WindowSettings, width, height, title, viewportFunc, drawFunc, keyFunc, mouseFunc, cursorFunc, errorFunc)
+}
 
-	if !glfw.Init() {
-		panic("can't init glfw!")
-	}
-	glfw.SetErrorCallback(errorFunc)
+func NewSimpleSharedApp(share *App, width, height int, title string, drawFunc func(*App)) *App {
+	return NewSharedApp(share, width, height, title, UpdateViewport, drawFunc, OnKeyDown, OnMouseDown, OnMouseMove)
+}
 
-	window, err := glfw.CreateWindow(width, height, title, nil, nil)
-	if err != nil {
-		panic(err)
-	}
+// opens another window, e.g. a tool window next to the main viewport, whose context shares buffers,
+// textures and programs with share. Start of any App draws all of them, only the first window waits for VSync.
+func NewSharedApp(share *App, width, height int, title string, viewportFunc func(*App), drawFunc func(*App), keyFunc func(*glfw.Window, glfw.Key, int, glfw.Action, glfw.ModifierKey), mouseFunc func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey), cursorFunc func(*glfw.Window, float64, float64)) *App {
+	config := WindowSettings
+	config.Mode = Windowed
+	config.VSync = false
+	// sharing needs compatible contexts
+	config.Major, config.Minor, config.Core = share.Config.Major, share.Config.Minor, share.Config.Core
 
-	window.MakeContextCurrent()
-	glfw.SwapInterval(1)
+	return newApp(share, config, width, height, title, viewportFunc, drawFunc, keyFunc, mouseFunc, cursorFunc, share.ErrorFunc)
+}
 
-	window.SetKeyCallback(keyFunc)
-	window.SetMouseButtonCallback(mouseFunc)
-	window.SetCursorPositionCallback(cursorFunc)
+func newApp(share *App, config WindowConfig, width, height int, title string, viewportFunc func(*App), drawFunc func(*App), keyFunc func(*glfw.Window, glfw.Key, int, glfw.Action, glfw.ModifierKey), mouseFunc func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey), cursorFunc func(*glfw.Window, float64, float64), errorFunc func(glfw.ErrorCode, string)) *App {
+	runtime.LockOSThread()
 
-	if gl.Init() != 0 {
-		panic("can't init glew!")
+	// glfw lives as long as any App
+	first := len(apps) == 0
+	if first {
+		if !glfw.Init() {
+			panic("can't init glfw!")
+		}
+		glfw.SetErrorCallback(errorFunc)
+		reportError = errorFunc
 	}
-	gl.GetError()
 
-	gl.Enable(gl.DEPTH_TEST)
-	gl.DepthFunc(gl.LESS)
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	gl.LineWidth(3)
+	var shareWindow *glfw.Window
+	if share != nil {
+		shareWindow = share.Window
+	}
+	window, err := createWindow(config, width, height, title, shareWindow)
+	if err != nil {
+		panic(err)
+	}
 
-	return &App{
+	a := &App{
 		Window:       window,
 		Width:        width,
 		Height:       height,
@@ -69,49 +114,203 @@ func NewApp(width, height int, title string, viewportFunc func(*App), drawFunc f
 		KeyFunc:      keyFunc,
 		MouseFunc:    mouseFunc,
 		CursorFunc:   cursorFunc,
+		ErrorFunc:    errorFunc,
+		Config:       config,
+		RenderState:  DefaultRenderState,
+		Culling:      NewCuller(),
+		windowed:     [4]int{0, 0, width, height},
+		state:        State,
+	}
+	if !first {
+		// every context has its own bindings
+		a.state = NewStateCache()
+		a.state.Debug = State.Debug
+		a.state.LazyUnbind = State.LazyUnbind
+	}
+	a.windowed[0], a.windowed[1] = window.GetPosition()
+	apps[window] = a
+	a.setCallbacks()
+	a.updateSize()
+	a.setupContext()
+	if first {
+		// shared programs are compiled for the first context
+		GLSL = DetectGLSL()
 	}
+
+	// driver messages end up next to the glfw errors
+	if DebugOutput.Enabled {
+		a.Debugging = EnableDebugOutput(reportDebugMessage)
+	}
+
+	return a
 }
 
+// glfw has a single error callback, set by the first App, GL debug messages go there too
+var reportError func(glfw.ErrorCode, string)
+
+func (a *App) setCallbacks() {
+	a.Window.SetKeyCallback(a.KeyFunc)
+	a.Window.SetMouseButtonCallback(a.MouseFunc)
+	a.Window.SetCursorPositionCallback(a.CursorFunc)
+	a.setEventCallbacks()
+}
+
+// runs the event loop until the window of a gets closed, drawing every open App once per loop.
+// Other windows are only hidden when closed, Destroy them after Start returns.
+// Minimized windows aren't drawn, while all of them are the loop sleeps until the next event.
 func (a *App) Start() {
+	last := glfw.GetTime()
 	for !a.Window.ShouldClose() {
-		a.ViewportFunc(a)
+		now := glfw.GetTime()
+		delta := now - last
+		last = now
 
-		gl.ClearColor(0.1, 0.1, 0.1, 1)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		drawn := false
+		for _, app := range openApps() {
+			if app != a && app.Window.ShouldClose() {
+				app.Window.Hide()
+				app.hidden = true
+				continue
+			}
+			if app.Iconified {
+				continue
+			}
+			app.frame(delta)
+			drawn = true
+		}
 
-		a.DrawFunc(a)
-		glh.OpenGLSentinel()
+		if drawn {
+			glfw.PollEvents()
+		} else {
+			glfw.WaitEvents()
+			// time stands still while minimized
+			last = glfw.GetTime()
+		}
+	}
+}
+
+// Apps with a visible window, in the order they were created
+func openApps() []*App {
+	ids := make([]int, 0, len(contexts))
+	for id, app := range contexts {
+		if !app.hidden {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
 
-		a.Window.SwapBuffers()
-		glfw.PollEvents()
+	open := make([]*App, len(ids))
+	for i, id := range ids {
+		open[i] = contexts[id]
 	}
+	return open
 }
 
+func (a *App) frame(delta float64) {
+	a.makeCurrent()
+	if a.Recorder != nil {
+		a.Delta = a.Recorder.Step
+	} else {
+		a.Delta = delta
+	}
+	a.Time += a.Delta
+
+	if a.toggleFullscreen {
+		// windows can't be destroyed from within their callbacks
+		a.toggleFullscreen = false
+		a.ToggleFullscreen()
+	}
+
+	Resources.LabelObjects()
+	a.ViewportFunc(a)
+
+	a.RenderState.Clear()
+
+	a.DrawFunc(a)
+	glh.OpenGLSentinel()
+	a.saveRequestedScreenshot()
+	if a.Recorder != nil {
+		a.Recorder.capture()
+	}
+	State.EndFrame()
+	a.Culling.EndFrame()
+
+	a.Window.SwapBuffers()
+}
+
+// closes the window unless a close handler vetoes it, see OnClose
 func (a *App) Close() {
-	a.Window.SetShouldClose(true)
+	if a.closeAllowed() {
+		a.Window.SetShouldClose(true)
+	}
 }
 
+// destroys the window, the last App to go frees all GL objects still alive and terminates glfw
 func (a *App) Destroy() {
+	a.makeCurrent()
 	glh.OpenGLSentinel()
+
+	if err := a.StopRecording(); err != nil {
+		log.Printf("can't save recording: %v\n", err)
+	}
+
+	delete(apps, a.Window)
+	if len(apps) > 0 {
+		// the other contexts still use the shared objects, the containers go down with this one
+		a.contextLost()
+		a.Window.Destroy()
+		currentContext = 0
+		for _, other := range apps {
+			other.makeCurrent()
+			break
+		}
+		return
+	}
+
+	// free whatever is still alive while the context is current
+	if report := Resources.LeakReport(); report != "" && Resources.ReportLeaks {
+		log.Print(report)
+	}
+	Resources.ReleaseAll()
+	a.contextLost()
+
 	a.Window.Destroy()
+	currentContext = 0
 	glfw.Terminate()
 }
 
+// Width, Height and Ratio follow the framebuffer size through events, see OnResize
 func UpdateViewport(a *App) {
-	w, h := a.Window.GetFramebufferSize()
-
-	gl.Viewport(0, 0, w, h)
-
-	a.Width = w
-	a.Height = h
-	a.Ratio = float32(w) / float32(h)
+	gl.Viewport(0, 0, a.Width, a.Height)
 }
 
 func OnKeyDown(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
 	log.Printf("Key [%v], Scancode [%v], Action [%v], Modifier [%v]\n", key, scancode, action, mod)
 
 	if key == glfw.KeyEscape && action == glfw.Press {
-		window.SetShouldClose(true)
+		if a := AppOf(window); a != nil {
+			a.Close()
+		} else {
+			window.SetShouldClose(true)
+		}
+	}
+
+	if key == RecordKey && action == glfw.Press {
+		if a := AppOf(window); a != nil {
+			a.toggleRecording()
+		}
+	}
+
+	if key == glfw.KeyEnter && action == glfw.Press && mod&glfw.ModAlt != 0 {
+		if a := AppOf(window); a != nil {
+			a.toggleFullscreen = true
+		}
+	}
+
+	if key == ScreenshotKey && action == glfw.Press {
+		if a := AppOf(window); a != nil {
+			a.RequestScreenshot()
+		}
 	}
 }
 
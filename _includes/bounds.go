@@ -0,0 +1,122 @@
+package _includes
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// axis-aligned bounding box
+type AABB struct {
+	Min mgl.Vec3
+	Max mgl.Vec3
+}
+
+type Sphere struct {
+	Center mgl.Vec3
+	Radius float32
+}
+
+// box around points, an empty box has Min greater than Max
+func BoundsOfPoints(points []mgl.Vec3) AABB {
+	inf := float32(math.Inf(1))
+	b := AABB{mgl.Vec3{inf, inf, inf}, mgl.Vec3{-inf, -inf, -inf}}
+	for _, p := range points {
+		for i := 0; i < 3; i++ {
+			if p[i] < b.Min[i] {
+				b.Min[i] = p[i]
+			}
+			if p[i] > b.Max[i] {
+				b.Max[i] = p[i]
+			}
+		}
+	}
+	return b
+}
+
+// box around the positions of a vertex slice
+func BoundsOf(vertices interface{}) AABB {
+	return BoundsOfPoints(PositionsOf(vertices))
+}
+
+// sphere around points, centered on their box, so not the smallest one but close
+func SphereOfPoints(points []mgl.Vec3) Sphere {
+	if len(points) == 0 {
+		return Sphere{}
+	}
+
+	center := BoundsOfPoints(points).Center()
+	var radius float32
+	for _, p := range points {
+		if d := p.Sub(center).Len(); d > radius {
+			radius = d
+		}
+	}
+	return Sphere{center, radius}
+}
+
+// sphere around the positions of a vertex slice
+func SphereOf(vertices interface{}) Sphere {
+	return SphereOfPoints(PositionsOf(vertices))
+}
+
+func (b AABB) Empty() bool {
+	return b.Min[0] > b.Max[0] || b.Min[1] > b.Max[1] || b.Min[2] > b.Max[2]
+}
+
+func (b AABB) Center() mgl.Vec3 {
+	return b.Min.Add(b.Max).Mul(0.5)
+}
+
+// half the size along each axis
+func (b AABB) Extents() mgl.Vec3 {
+	return b.Max.Sub(b.Min).Mul(0.5)
+}
+
+func (b AABB) Contains(p mgl.Vec3) bool {
+	return p[0] >= b.Min[0] && p[0] <= b.Max[0] &&
+		p[1] >= b.Min[1] && p[1] <= b.Max[1] &&
+		p[2] >= b.Min[2] && p[2] <= b.Max[2]
+}
+
+// smallest box containing both
+func (b AABB) Union(o AABB) AABB {
+	for i := 0; i < 3; i++ {
+		b.Min[i] = float32(math.Min(float64(b.Min[i]), float64(o.Min[i])))
+		b.Max[i] = float32(math.Max(float64(b.Max[i]), float64(o.Max[i])))
+	}
+	return b
+}
+
+// axis-aligned box around the transformed box, rotations make it grow
+func (b AABB) Transform(model mgl.Mat4) AABB {
+	if b.Empty() {
+		return b
+	}
+
+	center := mgl.TransformCoordinate(b.Center(), model)
+	extents := b.Extents()
+	var transformed mgl.Vec3
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			transformed[row] += float32(math.Abs(float64(model[col*4+row]))) * extents[col]
+		}
+	}
+	return AABB{center.Sub(transformed), center.Add(transformed)}
+}
+
+// sphere around the transformed sphere, non-uniform scaling makes it grow by the largest factor
+func (s Sphere) Transform(model mgl.Mat4) Sphere {
+	var scale float32
+	for col := 0; col < 3; col++ {
+		axis := mgl.Vec3{model[col*4], model[col*4+1], model[col*4+2]}
+		if l := axis.Len(); l > scale {
+			scale = l
+		}
+	}
+	return Sphere{mgl.TransformCoordinate(s.Center, model), s.Radius * scale}
+}
+
+func (s Sphere) Contains(p mgl.Vec3) bool {
+	return p.Sub(s.Center).Len() <= s.Radius
+}
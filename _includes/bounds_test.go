@@ -0,0 +1,102 @@
+package _includes
+
+import (
+	"math"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestAABBTransform(t *testing.T) {
+	unit := AABB{mgl.Vec3{-1, -1, -1}, mgl.Vec3{1, 1, 1}}
+	long := AABB{mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 1, 1}}
+	diagonal := float32(math.Sqrt2)
+
+	tests := []struct {
+		name  string
+		box   AABB
+		model mgl.Mat4
+		want  AABB
+	}{
+		{"identity", long, mgl.Ident4(), long},
+		{"translated", unit, mgl.Translate3D(1, 2, 3), AABB{mgl.Vec3{0, 1, 2}, mgl.Vec3{2, 3, 4}}},
+		{"scaled", long, mgl.Scale3D(2, 3, 4), AABB{mgl.Vec3{0, 0, 0}, mgl.Vec3{4, 3, 4}}},
+		{"mirrored", long, mgl.Scale3D(-1, 1, 1), AABB{mgl.Vec3{-2, 0, 0}, mgl.Vec3{0, 1, 1}}},
+		{"quarter turn", long, mgl.HomogRotate3D(math.Pi/2, mgl.Vec3{0, 0, 1}), AABB{mgl.Vec3{-1, 0, 0}, mgl.Vec3{0, 2, 1}}},
+		// rotating by 45 degrees makes the box grow to fit the corners
+		{"eighth turn", unit, mgl.HomogRotate3D(math.Pi/4, mgl.Vec3{0, 0, 1}), AABB{mgl.Vec3{-diagonal, -diagonal, -1}, mgl.Vec3{diagonal, diagonal, 1}}},
+		{"empty", BoundsOfPoints(nil), mgl.Translate3D(1, 2, 3), BoundsOfPoints(nil)},
+	}
+
+	for _, test := range tests {
+		got := test.box.Transform(test.model)
+		if test.want.Empty() {
+			if !got.Empty() {
+				t.Errorf("%s: %v, want an empty box", test.name, got)
+			}
+			continue
+		}
+		if !nearVec3(got.Min, test.want.Min) || !nearVec3(got.Max, test.want.Max) {
+			t.Errorf("%s: %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAABBTransformContainsCorners(t *testing.T) {
+	box := AABB{mgl.Vec3{-1, 0, 2}, mgl.Vec3{3, 1, 4}}
+	model := mgl.Translate3D(1, -2, 0.5).Mul4(mgl.HomogRotate3D(1, mgl.Vec3{1, 2, 3}.Normalize())).Mul4(mgl.Scale3D(1, 2, 0.5))
+	transformed := box.Transform(model)
+
+	// the grown box has to contain every transformed corner, with some room for rounding
+	transformed.Min = transformed.Min.Sub(mgl.Vec3{epsilon, epsilon, epsilon})
+	transformed.Max = transformed.Max.Add(mgl.Vec3{epsilon, epsilon, epsilon})
+	for i := 0; i < 8; i++ {
+		corner := box.Min
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<uint(axis)) != 0 {
+				corner[axis] = box.Max[axis]
+			}
+		}
+		if p := mgl.TransformCoordinate(corner, model); !transformed.Contains(p) {
+			t.Errorf("corner %v ends up at %v outside of %v", corner, p, transformed)
+		}
+	}
+}
+
+func TestSphereTransform(t *testing.T) {
+	sphere := Sphere{mgl.Vec3{1, 0, 0}, 2}
+	tests := []struct {
+		name  string
+		model mgl.Mat4
+		want  Sphere
+	}{
+		{"identity", mgl.Ident4(), sphere},
+		{"translated", mgl.Translate3D(0, 1, 0), Sphere{mgl.Vec3{1, 1, 0}, 2}},
+		{"rotated", mgl.HomogRotate3D(math.Pi/2, mgl.Vec3{0, 0, 1}), Sphere{mgl.Vec3{0, 1, 0}, 2}},
+		// non-uniform scales grow the radius by the largest factor
+		{"scaled", mgl.Scale3D(1, 3, 2), Sphere{mgl.Vec3{1, 0, 0}, 6}},
+	}
+
+	for _, test := range tests {
+		got := sphere.Transform(test.model)
+		if !nearVec3(got.Center, test.want.Center) || !near(got.Radius, test.want.Radius) {
+			t.Errorf("%s: %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSphereOfPoints(t *testing.T) {
+	points := []mgl.Vec3{{-1, 0, 0}, {3, 0, 0}, {1, 1, 0}}
+	sphere := SphereOfPoints(points)
+	if !nearVec3(sphere.Center, mgl.Vec3{1, 0.5, 0}) {
+		t.Errorf("center %v, want the center of the box", sphere.Center)
+	}
+	for _, p := range points {
+		if p.Sub(sphere.Center).Len() > sphere.Radius+epsilon {
+			t.Errorf("%v outside of %v", p, sphere)
+		}
+	}
+	if empty := SphereOfPoints(nil); empty != (Sphere{}) {
+		t.Errorf("sphere of no points %v, want the zero sphere", empty)
+	}
+}
@@ -0,0 +1,301 @@
+package _includes
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const debugVertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec4 color;
+
+		varying vec4 vertexColor;
+
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			vertexColor = color;
+			gl_Position = projection * view * position;
+		}
+`
+
+const debugFragmentShaderSource = `
+	#version 130
+		varying vec4 vertexColor;
+
+		void main() {
+			gl_FragColor = vertexColor;
+		}
+`
+
+const debugSegments = 32
+
+type debugPrimitive struct {
+	vertices  ColorVertices
+	mode      gl.GLenum
+	depthTest bool
+	frames    int
+}
+
+// collects lines and points during a frame and draws them all at once on Flush,
+// DepthTest and Lifetime (in frames) apply to every primitive added after changing them
+type DebugDraw struct {
+	Shader     *Shader
+	DepthTest  bool
+	Lifetime   int
+	PointSize  float32
+	primitives []*debugPrimitive
+	vertices   ColorVertices
+	capacity   int
+}
+
+func NewDebugDraw() *DebugDraw {
+	d := &DebugDraw{
+		DepthTest: true,
+		Lifetime:  1,
+		PointSize: 5,
+		capacity:  1024,
+	}
+
+	shader := NewShader(debugVertexShaderSource, debugFragmentShaderSource)
+	shader.SetVertexArray()
+	shader.SetVertexArrayBuffer(make(ColorVertices, d.capacity), gl.DYNAMIC_DRAW)
+
+	shader.EnableColorVertexAttributes()
+	shader.SetUniformLocations()
+
+	shader.Unuse()
+	glh.OpenGLSentinel()
+
+	d.Shader = shader
+	return d
+}
+
+func (d *DebugDraw) Delete() {
+	d.Shader.Delete()
+	d.primitives = nil
+}
+
+func (d *DebugDraw) add(mode gl.GLenum, vertices ColorVertices) {
+	frames := d.Lifetime
+	if frames < 1 {
+		frames = 1
+	}
+	d.primitives = append(d.primitives, &debugPrimitive{
+		vertices:  vertices,
+		mode:      mode,
+		depthTest: d.DepthTest,
+		frames:    frames,
+	})
+}
+
+func debugVertex(p mgl.Vec3, color mgl.Vec4) ColorVertex {
+	return ColorVertex{Position: p.Vec4(1), Color: color}
+}
+
+func (d *DebugDraw) Point(p mgl.Vec3, color mgl.Vec4) {
+	d.add(gl.POINTS, ColorVertices{debugVertex(p, color)})
+}
+
+func (d *DebugDraw) Line(from, to mgl.Vec3, color mgl.Vec4) {
+	d.add(gl.LINES, ColorVertices{debugVertex(from, color), debugVertex(to, color)})
+}
+
+// lines between consecutive pairs of points
+func (d *DebugDraw) Lines(points []mgl.Vec3, color mgl.Vec4) {
+	vertices := make(ColorVertices, 0, len(points))
+	for i := 0; i+1 < len(points); i += 2 {
+		vertices = append(vertices, debugVertex(points[i], color), debugVertex(points[i+1], color))
+	}
+	d.add(gl.LINES, vertices)
+}
+
+func (d *DebugDraw) WireBox(min, max mgl.Vec3, color mgl.Vec4) {
+	d.OrientedBox(mgl.Ident4(), min, max, color)
+}
+
+func (d *DebugDraw) OrientedBox(transform mgl.Mat4, min, max mgl.Vec3, color mgl.Vec4) {
+	var corners [8]mgl.Vec3
+	for i := range corners {
+		corner := min
+		if i&1 != 0 {
+			corner[0] = max[0]
+		}
+		if i&2 != 0 {
+			corner[1] = max[1]
+		}
+		if i&4 != 0 {
+			corner[2] = max[2]
+		}
+		corners[i] = mgl.TransformCoordinate(corner, transform)
+	}
+	d.boxEdges(corners, color)
+}
+
+// corners are indexed by bit pattern: bit 0 = x, bit 1 = y, bit 2 = z
+func (d *DebugDraw) boxEdges(corners [8]mgl.Vec3, color mgl.Vec4) {
+	edges := [12][2]int{
+		{0, 1}, {2, 3}, {4, 5}, {6, 7},
+		{0, 2}, {1, 3}, {4, 6}, {5, 7},
+		{0, 4}, {1, 5}, {2, 6}, {3, 7},
+	}
+
+	vertices := make(ColorVertices, 0, len(edges)*2)
+	for _, e := range edges {
+		vertices = append(vertices, debugVertex(corners[e[0]], color), debugVertex(corners[e[1]], color))
+	}
+	d.add(gl.LINES, vertices)
+}
+
+// approximates a sphere with three great circles
+func (d *DebugDraw) Sphere(center mgl.Vec3, radius float32, color mgl.Vec4) {
+	vertices := make(ColorVertices, 0, debugSegments*6)
+	for axis := 0; axis < 3; axis++ {
+		for i := 0; i < debugSegments; i++ {
+			for _, s := range []int{i, i + 1} {
+				angle := 2 * math.Pi * float64(s) / debugSegments
+				a, b := radius*float32(math.Cos(angle)), radius*float32(math.Sin(angle))
+
+				var offset mgl.Vec3
+				switch axis {
+				case 0:
+					offset = mgl.Vec3{0, a, b}
+				case 1:
+					offset = mgl.Vec3{a, 0, b}
+				case 2:
+					offset = mgl.Vec3{a, b, 0}
+				}
+				vertices = append(vertices, debugVertex(center.Add(offset), color))
+			}
+		}
+	}
+	d.add(gl.LINES, vertices)
+}
+
+// draws the x, y and z axes of transform in red, green and blue
+func (d *DebugDraw) Axes(transform mgl.Mat4, size float32) {
+	origin := mgl.TransformCoordinate(mgl.Vec3{0, 0, 0}, transform)
+	d.add(gl.LINES, ColorVertices{
+		debugVertex(origin, mgl.Vec4{1, 0, 0, 1}), debugVertex(mgl.TransformCoordinate(mgl.Vec3{size, 0, 0}, transform), mgl.Vec4{1, 0, 0, 1}),
+		debugVertex(origin, mgl.Vec4{0, 1, 0, 1}), debugVertex(mgl.TransformCoordinate(mgl.Vec3{0, size, 0}, transform), mgl.Vec4{0, 1, 0, 1}),
+		debugVertex(origin, mgl.Vec4{0, 0, 1, 1}), debugVertex(mgl.TransformCoordinate(mgl.Vec3{0, 0, size}, transform), mgl.Vec4{0, 0, 1, 1}),
+	})
+}
+
+// grid on the xz plane around center, size is the full edge length
+func (d *DebugDraw) Grid(center mgl.Vec3, size float32, divisions int, color mgl.Vec4) {
+	if divisions < 1 {
+		divisions = 1
+	}
+
+	half := size / 2
+	step := size / float32(divisions)
+	vertices := make(ColorVertices, 0, (divisions+1)*4)
+	for i := 0; i <= divisions; i++ {
+		t := -half + float32(i)*step
+		vertices = append(vertices,
+			debugVertex(center.Add(mgl.Vec3{t, 0, -half}), color), debugVertex(center.Add(mgl.Vec3{t, 0, half}), color),
+			debugVertex(center.Add(mgl.Vec3{-half, 0, t}), color), debugVertex(center.Add(mgl.Vec3{half, 0, t}), color),
+		)
+	}
+	d.add(gl.LINES, vertices)
+}
+
+// draws the frustum described by a projection * view matrix
+func (d *DebugDraw) Frustum(viewProjection mgl.Mat4, color mgl.Vec4) {
+	inverse := viewProjection.Inv()
+
+	var corners [8]mgl.Vec3
+	for i := range corners {
+		ndc := mgl.Vec3{-1, -1, -1}
+		if i&1 != 0 {
+			ndc[0] = 1
+		}
+		if i&2 != 0 {
+			ndc[1] = 1
+		}
+		if i&4 != 0 {
+			ndc[2] = 1
+		}
+		corners[i] = mgl.TransformCoordinate(ndc, inverse)
+	}
+	d.boxEdges(corners, color)
+}
+
+// uploads all collected primitives at once and draws them,
+// primitives whose lifetime ran out are dropped afterwards
+func (d *DebugDraw) Flush(view, projection mgl.Mat4) {
+	type batch struct {
+		mode      gl.GLenum
+		depthTest bool
+		first     int
+		count     int
+	}
+
+	// group vertices by draw mode and depth test so each group is a single draw call
+	d.vertices = d.vertices[:0]
+	batches := make([]batch, 0, 4)
+	for _, depthTest := range []bool{true, false} {
+		for _, mode := range []gl.GLenum{gl.LINES, gl.POINTS} {
+			b := batch{mode: mode, depthTest: depthTest, first: len(d.vertices)}
+			for _, p := range d.primitives {
+				if p.mode == mode && p.depthTest == depthTest {
+					d.vertices = append(d.vertices, p.vertices...)
+				}
+			}
+			b.count = len(d.vertices) - b.first
+			if b.count > 0 {
+				batches = append(batches, b)
+			}
+		}
+	}
+
+	if len(d.vertices) > 0 {
+		d.Shader.Use()
+		d.Shader.View.UniformMatrix4fv(false, view)
+		d.Shader.Projection.UniformMatrix4fv(false, projection)
+
+		size := len(d.vertices) * int(unsafe.Sizeof(ColorVertex{}))
+		if len(d.vertices) > d.capacity {
+			d.capacity = len(d.vertices)
+			gl.BufferData(gl.ARRAY_BUFFER, size, d.vertices, gl.DYNAMIC_DRAW)
+		} else {
+			gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, d.vertices)
+		}
+
+		gl.PointSize(d.PointSize)
+		// batches without depth testing turn it off, afterwards it's back to what the App had
+		depthTest := State.Enabled(gl.DEPTH_TEST)
+		for _, b := range batches {
+			State.SetEnabled(gl.DEPTH_TEST, depthTest && b.depthTest)
+			gl.DrawArrays(b.mode, b.first, b.count)
+		}
+		State.SetEnabled(gl.DEPTH_TEST, depthTest)
+
+		d.Shader.Unuse()
+		glh.OpenGLSentinel()
+	}
+
+	// age primitives
+	alive := d.primitives[:0]
+	for _, p := range d.primitives {
+		p.frames--
+		if p.frames > 0 {
+			alive = append(alive, p)
+		}
+	}
+	for i := len(alive); i < len(d.primitives); i++ {
+		d.primitives[i] = nil
+	}
+	d.primitives = alive
+}
+
+func (d *DebugDraw) Clear() {
+	d.primitives = nil
+}
@@ -0,0 +1,27 @@
+#include <GL/glew.h>
+#include "_cgo_export.h"
+
+static void GLAPIENTRY debugCallback(GLenum source, GLenum type, GLuint id, GLenum severity, GLsizei length, const GLchar *message, const void *userParam) {
+	goDebugMessage(source, type, id, severity, (char *)message);
+}
+
+int enableDebugOutput(int synchronous) {
+	if (glDebugMessageCallback == NULL) {
+		return 0;
+	}
+	glEnable(GL_DEBUG_OUTPUT);
+	// call back on the thread that made the GL call, so the Go stack is meaningful
+	if (synchronous) {
+		glEnable(GL_DEBUG_OUTPUT_SYNCHRONOUS);
+	} else {
+		glDisable(GL_DEBUG_OUTPUT_SYNCHRONOUS);
+	}
+	glDebugMessageCallback((GLDEBUGPROC)debugCallback, NULL);
+	return 1;
+}
+
+void objectLabel(GLenum identifier, GLuint name, const char *label) {
+	if (glObjectLabel != NULL) {
+		glObjectLabel(identifier, name, -1, label);
+	}
+}
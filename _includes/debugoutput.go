@@ -0,0 +1,185 @@
+package _includes
+
+/*
+#cgo linux LDFLAGS: -lGLEW -lGL
+#cgo darwin LDFLAGS: -lGLEW -framework OpenGL
+#cgo windows LDFLAGS: -lglew32 -lopengl32
+#include <stdlib.h>
+#include <GL/glew.h>
+
+int enableDebugOutput(int synchronous);
+void objectLabel(GLenum identifier, GLuint name, const char *label);
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime/debug"
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+)
+
+// error code GL debug messages are passed to App.ErrorFunc with, next to the glfw errors
+const DebugOutputError glfw.ErrorCode = 0x0001FFFF
+
+type DebugSeverity int
+
+const (
+	DebugNotification DebugSeverity = iota
+	DebugLow
+	DebugMedium
+	DebugHigh
+)
+
+var debugSeverities = map[C.GLenum]DebugSeverity{
+	C.GL_DEBUG_SEVERITY_NOTIFICATION: DebugNotification,
+	C.GL_DEBUG_SEVERITY_LOW:          DebugLow,
+	C.GL_DEBUG_SEVERITY_MEDIUM:       DebugMedium,
+	C.GL_DEBUG_SEVERITY_HIGH:         DebugHigh,
+}
+
+func (s DebugSeverity) String() string {
+	return [...]string{"notification", "low", "medium", "high"}[s]
+}
+
+var debugSources = map[C.GLenum]string{
+	C.GL_DEBUG_SOURCE_API:             "API",
+	C.GL_DEBUG_SOURCE_WINDOW_SYSTEM:   "window system",
+	C.GL_DEBUG_SOURCE_SHADER_COMPILER: "shader compiler",
+	C.GL_DEBUG_SOURCE_THIRD_PARTY:     "third party",
+	C.GL_DEBUG_SOURCE_APPLICATION:     "application",
+	C.GL_DEBUG_SOURCE_OTHER:           "other",
+}
+
+var debugTypes = map[C.GLenum]string{
+	C.GL_DEBUG_TYPE_ERROR:               "error",
+	C.GL_DEBUG_TYPE_DEPRECATED_BEHAVIOR: "deprecated behavior",
+	C.GL_DEBUG_TYPE_UNDEFINED_BEHAVIOR:  "undefined behavior",
+	C.GL_DEBUG_TYPE_PORTABILITY:         "portability",
+	C.GL_DEBUG_TYPE_PERFORMANCE:         "performance",
+	C.GL_DEBUG_TYPE_MARKER:              "marker",
+	C.GL_DEBUG_TYPE_PUSH_GROUP:          "push group",
+	C.GL_DEBUG_TYPE_POP_GROUP:           "pop group",
+	C.GL_DEBUG_TYPE_OTHER:               "other",
+}
+
+// message reported by the driver through KHR_debug
+type DebugMessage struct {
+	Source   string
+	Type     string
+	ID       uint
+	Severity DebugSeverity
+	Message  string
+	// Go stack of the GL call that caused the message, see DebugConfig.CaptureStack
+	Stack []byte
+}
+
+func (m DebugMessage) Error() string {
+	return fmt.Sprintf("GL %v %v (%v severity, id %d): %v", m.Source, m.Type, m.Severity, m.ID, m.Message)
+}
+
+type DebugConfig struct {
+	Enabled bool
+	// report messages on the thread of the GL call that caused them, slows every GL call down,
+	// but without it messages arrive late and ErrorFunc gets called from driver threads
+	Synchronous bool
+	// messages below this severity are dropped
+	MinSeverity DebugSeverity
+	// capture the Go stack for messages at or above StackSeverity, only with Synchronous
+	CaptureStack  bool
+	StackSeverity DebugSeverity
+	// label GL objects with the function that created them, so messages can name them
+	Labels bool
+}
+
+// debug output NewApp enables when the driver supports it, set Enabled before creating the App
+var DebugOutput = DebugConfig{
+	Synchronous:   true,
+	MinSeverity:   DebugLow,
+	CaptureStack:  true,
+	StackSeverity: DebugHigh,
+	Labels:        true,
+}
+
+var debugFunc func(DebugMessage)
+
+// routes GL debug messages into f, returns false when the driver has no KHR_debug
+func EnableDebugOutput(f func(DebugMessage)) bool {
+	synchronous := C.int(0)
+	if DebugOutput.Synchronous {
+		synchronous = 1
+	}
+	if C.enableDebugOutput(synchronous) == 0 {
+		return false
+	}
+	debugFunc = f
+	return true
+}
+
+//export goDebugMessage
+func goDebugMessage(source, typ C.GLenum, id C.GLuint, severity C.GLenum, message *C.char) {
+	if debugFunc == nil {
+		return
+	}
+
+	m := DebugMessage{
+		Source:   debugSources[source],
+		Type:     debugTypes[typ],
+		ID:       uint(id),
+		Severity: debugSeverities[severity],
+		Message:  C.GoString(message),
+	}
+	if m.Severity < DebugOutput.MinSeverity {
+		return
+	}
+	if DebugOutput.Synchronous && DebugOutput.CaptureStack && m.Severity >= DebugOutput.StackSeverity {
+		m.Stack = debug.Stack()
+	}
+
+	debugFunc(m)
+}
+
+// names a GL object in debug messages, does nothing without debug output
+func Label(object interface{}, label string) {
+	if debugFunc == nil {
+		return
+	}
+
+	var identifier C.GLenum
+	var name C.GLuint
+	switch o := object.(type) {
+	case gl.Buffer:
+		identifier, name = C.GL_BUFFER, C.GLuint(o)
+	case gl.Program:
+		identifier, name = C.GL_PROGRAM, C.GLuint(o)
+	case gl.VertexArray:
+		identifier, name = C.GL_VERTEX_ARRAY, C.GLuint(o)
+	case gl.Texture:
+		identifier, name = C.GL_TEXTURE, C.GLuint(o)
+	case gl.Framebuffer:
+		identifier, name = C.GL_FRAMEBUFFER, C.GLuint(o)
+	case gl.Renderbuffer:
+		identifier, name = C.GL_RENDERBUFFER, C.GLuint(o)
+	default:
+		return
+	}
+
+	s := C.CString(label)
+	defer C.free(unsafe.Pointer(s))
+	C.objectLabel(identifier, name, s)
+}
+
+// passes a message on to the error function of the first App, the one glfw reports to
+func reportDebugMessage(m DebugMessage) {
+	if reportError == nil {
+		return
+	}
+
+	description := m.Error()
+	if m.Stack != nil {
+		description += "\n" + string(m.Stack)
+	}
+	reportError(DebugOutputError, description)
+}
@@ -0,0 +1,144 @@
+package _includes
+
+import (
+	glfw "github.com/go-gl/glfw3"
+)
+
+// handlers registered on an App, they run on the main thread from within PollEvents
+type appEvents struct {
+	resize  []func(a *App, width, height int)
+	focus   []func(a *App, focused bool)
+	iconify []func(a *App, iconified bool)
+	scale   []func(a *App, x, y float32)
+	close   []func(a *App) bool
+}
+
+// called with the new framebuffer size in pixels, Width, Height and Ratio are already updated
+func (a *App) OnResize(f func(a *App, width, height int)) {
+	a.events.resize = append(a.events.resize, f)
+}
+
+func (a *App) OnFocus(f func(a *App, focused bool)) {
+	a.events.focus = append(a.events.focus, f)
+}
+
+// the App doesn't draw while its window is minimized
+func (a *App) OnIconify(f func(a *App, iconified bool)) {
+	a.events.iconify = append(a.events.iconify, f)
+}
+
+// called when the ratio of framebuffer pixels to window coordinates changes,
+// e.g. when the window gets moved to a monitor with another DPI
+func (a *App) OnContentScale(f func(a *App, x, y float32)) {
+	a.events.scale = append(a.events.scale, f)
+}
+
+// called when the window is about to close, returning false from any handler keeps it open
+func (a *App) OnClose(f func(a *App) bool) {
+	a.events.close = append(a.events.close, f)
+}
+
+func (a *App) setEventCallbacks() {
+	a.Window.SetFramebufferSizeCallback(onFramebufferSize)
+	a.Window.SetSizeCallback(onWindowSize)
+	a.Window.SetFocusCallback(onFocus)
+	a.Window.SetIconifyCallback(onIconify)
+	a.Window.SetCloseCallback(onClose)
+
+	a.Focused = a.Window.GetAttribute(glfw.Focused) == glfw.True
+	a.Iconified = a.Window.GetAttribute(glfw.Iconified) == glfw.True
+}
+
+// reads the framebuffer size and content scale, returns whether they changed
+func (a *App) updateSize() (resized, rescaled bool) {
+	w, h := a.Window.GetFramebufferSize()
+	ww, wh := a.Window.GetSize()
+	if w == 0 || h == 0 || ww == 0 || wh == 0 {
+		// minimized
+		return false, false
+	}
+
+	resized = w != a.Width || h != a.Height
+	a.Width = w
+	a.Height = h
+	a.Ratio = float32(w) / float32(h)
+
+	x, y := float32(w)/float32(ww), float32(h)/float32(wh)
+	rescaled = x != a.ScaleX || y != a.ScaleY
+	a.ScaleX = x
+	a.ScaleY = y
+	return resized, rescaled
+}
+
+// glfw has no content scale event, the scale follows framebuffer and window size
+func (a *App) sizeChanged() {
+	resized, rescaled := a.updateSize()
+	if resized {
+		for _, f := range a.events.resize {
+			f(a, a.Width, a.Height)
+		}
+	}
+	if rescaled {
+		for _, f := range a.events.scale {
+			f(a, a.ScaleX, a.ScaleY)
+		}
+	}
+}
+
+func onFramebufferSize(window *glfw.Window, width, height int) {
+	if a := AppOf(window); a != nil {
+		a.sizeChanged()
+	}
+}
+
+func onWindowSize(window *glfw.Window, width, height int) {
+	if a := AppOf(window); a != nil {
+		a.sizeChanged()
+	}
+}
+
+func onFocus(window *glfw.Window, focused bool) {
+	a := AppOf(window)
+	if a == nil {
+		return
+	}
+
+	a.Focused = focused
+	for _, f := range a.events.focus {
+		f(a, focused)
+	}
+}
+
+func onIconify(window *glfw.Window, iconified bool) {
+	a := AppOf(window)
+	if a == nil {
+		return
+	}
+
+	a.Iconified = iconified
+	for _, f := range a.events.iconify {
+		f(a, iconified)
+	}
+}
+
+// glfw sets the close flag before calling this, a veto clears it again
+func onClose(window *glfw.Window) {
+	a := AppOf(window)
+	if a == nil {
+		return
+	}
+
+	if !a.closeAllowed() {
+		window.SetShouldClose(false)
+	}
+}
+
+func (a *App) closeAllowed() bool {
+	allowed := true
+	for _, f := range a.events.close {
+		if !f(a) {
+			allowed = false
+		}
+	}
+	return allowed
+}
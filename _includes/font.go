@@ -0,0 +1,249 @@
+package _includes
+
+import (
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"unicode/utf8"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+type TextAlign int
+
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+)
+
+const glyphPadding = 1
+
+type Glyph struct {
+	Rune    rune
+	Offset  image.Point // top-left corner relative to the pen position on the baseline
+	Size    image.Point
+	Advance float32
+	UV      mgl.Vec4 // u0, v0, u1, v1 inside the atlas
+}
+
+type Font struct {
+	Face       font.Face
+	Atlas      *image.NRGBA
+	Glyphs     map[rune]*Glyph
+	Ascent     float32
+	Descent    float32
+	LineHeight float32
+}
+
+// printable ASCII, used if no explicit rune set is given
+func DefaultRunes() []rune {
+	runes := make([]rune, 0, 95)
+	for r := rune(32); r < 127; r++ {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+func LoadFont(filename string, size, dpi float64, runes []rune) *Font {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+	return NewFont(data, size, dpi, runes)
+}
+
+func NewFont(data []byte, size, dpi float64, runes []rune) *Font {
+	// parse truetype / opentype data
+	otf, err := opentype.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+
+	face, err := opentype.NewFace(otf, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	if len(runes) == 0 {
+		runes = DefaultRunes()
+	}
+
+	metrics := face.Metrics()
+	f := &Font{
+		Face:       face,
+		Glyphs:     make(map[rune]*Glyph),
+		Ascent:     float32(metrics.Ascent) / 64,
+		Descent:    float32(metrics.Descent) / 64,
+		LineHeight: float32(metrics.Height) / 64,
+	}
+	f.rasterize(runes)
+
+	return f
+}
+
+func (f *Font) rasterize(runes []rune) {
+	type placement struct {
+		glyph *Glyph
+		x, y  int
+	}
+
+	// measure all glyphs and pack them row by row
+	width := 512
+	x, y, rowHeight := glyphPadding, glyphPadding, 0
+	placements := make([]placement, 0, len(runes))
+	for _, r := range runes {
+		if _, ok := f.Glyphs[r]; ok {
+			continue
+		}
+
+		bounds, advance, ok := f.Face.GlyphBounds(r)
+		if !ok {
+			continue
+		}
+
+		min := image.Point{bounds.Min.X.Floor(), bounds.Min.Y.Floor()}
+		max := image.Point{bounds.Max.X.Ceil(), bounds.Max.Y.Ceil()}
+		glyph := &Glyph{
+			Rune:    r,
+			Offset:  min,
+			Size:    max.Sub(min),
+			Advance: float32(advance) / 64,
+		}
+		f.Glyphs[r] = glyph
+
+		if x+glyph.Size.X+glyphPadding > width {
+			x = glyphPadding
+			y += rowHeight + glyphPadding
+			rowHeight = 0
+		}
+		placements = append(placements, placement{glyph, x, y})
+
+		x += glyph.Size.X + glyphPadding
+		if glyph.Size.Y > rowHeight {
+			rowHeight = glyph.Size.Y
+		}
+	}
+
+	height := 1
+	for height < y+rowHeight+glyphPadding {
+		height *= 2
+	}
+
+	// draw glyph masks as white pixels into the atlas, coverage goes into alpha
+	f.Atlas = image.NewNRGBA(image.Rect(0, 0, width, height))
+	for _, p := range placements {
+		dot := fixed.P(p.x-p.glyph.Offset.X, p.y-p.glyph.Offset.Y)
+		dr, mask, maskp, _, ok := f.Face.Glyph(dot, p.glyph.Rune)
+		if !ok {
+			continue
+		}
+		draw.DrawMask(f.Atlas, dr, image.White, image.Point{}, mask, maskp, draw.Over)
+
+		p.glyph.UV = mgl.Vec4{
+			float32(p.x) / float32(width),
+			float32(p.y) / float32(height),
+			float32(p.x+p.glyph.Size.X) / float32(width),
+			float32(p.y+p.glyph.Size.Y) / float32(height),
+		}
+	}
+}
+
+// returns the width of every line and the total height of the text block, in pixels
+func (f *Font) Measure(text string) ([]float32, float32) {
+	widths := []float32{0}
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			widths = append(widths, 0)
+			prev = -1
+			continue
+		}
+
+		glyph, ok := f.Glyphs[r]
+		if !ok {
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			widths[len(widths)-1] += float32(f.Face.Kern(prev, r)) / 64
+		}
+		widths[len(widths)-1] += glyph.Advance
+		prev = r
+	}
+
+	return widths, float32(len(widths)) * f.LineHeight
+}
+
+// turns a string into textured quads, 4 vertices per glyph,
+// origin is the top-left corner of the text block and y grows downwards (pixels)
+func (f *Font) Layout(text string, align TextAlign) TextureVertices {
+	widths, _ := f.Measure(text)
+	block := float32(0)
+	for _, w := range widths {
+		if w > block {
+			block = w
+		}
+	}
+
+	vertices := make(TextureVertices, 0, utf8.RuneCountInString(text)*4)
+
+	line := 0
+	penX := alignOffset(align, block, widths[line])
+	penY := f.Ascent
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			line++
+			penX = alignOffset(align, block, widths[line])
+			penY += f.LineHeight
+			prev = -1
+			continue
+		}
+
+		glyph, ok := f.Glyphs[r]
+		if !ok {
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			penX += float32(f.Face.Kern(prev, r)) / 64
+		}
+		prev = r
+
+		if glyph.Size.X > 0 && glyph.Size.Y > 0 {
+			x0 := penX + float32(glyph.Offset.X)
+			y0 := penY + float32(glyph.Offset.Y)
+			x1 := x0 + float32(glyph.Size.X)
+			y1 := y0 + float32(glyph.Size.Y)
+			u0, v0, u1, v1 := glyph.UV[0], glyph.UV[1], glyph.UV[2], glyph.UV[3]
+
+			vertices = append(vertices,
+				TextureVertex{Position: mgl.Vec4{x0, y1, 0, 1}, TextureCoordinate: mgl.Vec2{u0, v1}},
+				TextureVertex{Position: mgl.Vec4{x1, y1, 0, 1}, TextureCoordinate: mgl.Vec2{u1, v1}},
+				TextureVertex{Position: mgl.Vec4{x1, y0, 0, 1}, TextureCoordinate: mgl.Vec2{u1, v0}},
+				TextureVertex{Position: mgl.Vec4{x0, y0, 0, 1}, TextureCoordinate: mgl.Vec2{u0, v0}},
+			)
+		}
+		penX += glyph.Advance
+	}
+
+	return vertices
+}
+
+func alignOffset(align TextAlign, block, width float32) float32 {
+	switch align {
+	case AlignCenter:
+		return (block - width) / 2
+	case AlignRight:
+		return block - width
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package _includes
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// planes a, b, c, d with normals pointing inside, normalized so that
+// a*x + b*y + c*z + d is the signed distance of a point
+type Frustum struct {
+	Planes [6]mgl.Vec4
+}
+
+// extracts left, right, bottom, top, near and far planes of a projection * view matrix,
+// for projection alone the planes are in view space
+func FrustumOf(viewProjection mgl.Mat4) Frustum {
+	row := func(r int) mgl.Vec4 {
+		m := viewProjection
+		return mgl.Vec4{m[r], m[4+r], m[8+r], m[12+r]}
+	}
+	x, y, z, w := row(0), row(1), row(2), row(3)
+
+	f := Frustum{[6]mgl.Vec4{
+		w.Add(x), w.Sub(x),
+		w.Add(y), w.Sub(y),
+		w.Add(z), w.Sub(z),
+	}}
+	for i, p := range f.Planes {
+		length := mgl.Vec3{p[0], p[1], p[2]}.Len()
+		if length > 0 {
+			f.Planes[i] = p.Mul(1 / length)
+		}
+	}
+	return f
+}
+
+func (f Frustum) distance(plane int, p mgl.Vec3) float32 {
+	n := f.Planes[plane]
+	return n[0]*p[0] + n[1]*p[1] + n[2]*p[2] + n[3]
+}
+
+func (f Frustum) ContainsPoint(p mgl.Vec3) bool {
+	for i := range f.Planes {
+		if f.distance(i, p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// false only if the sphere is entirely outside
+func (f Frustum) IntersectsSphere(s Sphere) bool {
+	for i := range f.Planes {
+		if f.distance(i, s.Center) < -s.Radius {
+			return false
+		}
+	}
+	return true
+}
+
+// false only if the box is entirely outside of one plane, boxes near the corners may pass even though outside
+func (f Frustum) IntersectsBox(b AABB) bool {
+	if b.Empty() {
+		return false
+	}
+
+	center := b.Center()
+	extents := b.Extents()
+	for i, n := range f.Planes {
+		// how far the box reaches towards the plane normal
+		r := extents[0]*abs32(n[0]) + extents[1]*abs32(n[1]) + extents[2]*abs32(n[2])
+		if f.distance(i, center) < -r {
+			return false
+		}
+	}
+	return true
+}
+
+func abs32(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// tests objects against the view frustum so their draws can be skipped, counting per frame like StateCache.
+// Every App has one, set the view and projection at the start of each frame and draw through Mesh.DrawCulled.
+type Culler struct {
+	Frustum Frustum
+	// when false everything is visible
+	Enabled bool
+	// objects tested and culled since the last EndFrame
+	Tested int
+	Culled int
+	// counters of the previous frame
+	LastFrameTested int
+	LastFrameCulled int
+}
+
+func NewCuller() *Culler {
+	return &Culler{Enabled: true}
+}
+
+func (c *Culler) SetViewProjection(view, projection mgl.Mat4) {
+	c.Frustum = FrustumOf(projection.Mul4(view))
+}
+
+// whether bounds, placed in the world by model, are at least partially inside the frustum
+func (c *Culler) Visible(bounds AABB, model mgl.Mat4) bool {
+	return c.count(!c.Enabled || c.Frustum.IntersectsBox(bounds.Transform(model)))
+}
+
+func (c *Culler) VisibleSphere(sphere Sphere, model mgl.Mat4) bool {
+	return c.count(!c.Enabled || c.Frustum.IntersectsSphere(sphere.Transform(model)))
+}
+
+// tests the cheaper bounding sphere of the mesh first and its box after that
+func (c *Culler) VisibleMesh(mesh *Mesh, model mgl.Mat4) bool {
+	if !c.Enabled {
+		return c.count(true)
+	}
+	return c.count(c.Frustum.IntersectsSphere(mesh.Sphere.Transform(model)) &&
+		c.Frustum.IntersectsBox(mesh.Bounds.Transform(model)))
+}
+
+func (c *Culler) count(visible bool) bool {
+	c.Tested++
+	if !visible {
+		c.Culled++
+	}
+	return visible
+}
+
+// keeps the counters of the finished frame and starts counting again, App.Start calls this after every frame
+func (c *Culler) EndFrame() {
+	c.LastFrameTested = c.Tested
+	c.LastFrameCulled = c.Culled
+	c.Tested = 0
+	c.Culled = 0
+}
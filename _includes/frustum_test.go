@@ -0,0 +1,117 @@
+package _includes
+
+import (
+	"math"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestFrustumOf(t *testing.T) {
+	// for an orthographic projection the planes are the box sides, normals pointing inside
+	frustum := FrustumOf(mgl.Ortho(-2, 2, -3, 3, 1, 10))
+	planes := []struct {
+		name  string
+		plane mgl.Vec4
+	}{
+		{"left", mgl.Vec4{1, 0, 0, 2}},
+		{"right", mgl.Vec4{-1, 0, 0, 2}},
+		{"bottom", mgl.Vec4{0, 1, 0, 3}},
+		{"top", mgl.Vec4{0, -1, 0, 3}},
+		// the camera looks along -z, so the near plane is at z = -1
+		{"near", mgl.Vec4{0, 0, -1, -1}},
+		{"far", mgl.Vec4{0, 0, 1, 10}},
+	}
+
+	for i, test := range planes {
+		if !nearVec4(frustum.Planes[i], test.plane) {
+			t.Errorf("%s plane %v, want %v", test.name, frustum.Planes[i], test.plane)
+		}
+	}
+}
+
+func TestFrustumContains(t *testing.T) {
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 5}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(math.Pi/2, 1, 1, 20)
+	frustum := FrustumOf(projection.Mul4(view))
+
+	tests := []struct {
+		name   string
+		point  mgl.Vec3
+		inside bool
+	}{
+		{"center", mgl.Vec3{0, 0, 0}, true},
+		{"near the far plane", mgl.Vec3{0, 0, -14}, true},
+		{"beyond the far plane", mgl.Vec3{0, 0, -16}, false},
+		{"between camera and near plane", mgl.Vec3{0, 0, 4.5}, false},
+		{"behind the camera", mgl.Vec3{0, 0, 10}, false},
+		// a 90 degree field of view reaches 5 units to the side at a distance of 5
+		{"inside the right side", mgl.Vec3{4.9, 0, 0}, true},
+		{"outside the right side", mgl.Vec3{5.1, 0, 0}, false},
+		{"outside the top", mgl.Vec3{0, 5.1, 0}, false},
+	}
+
+	for _, test := range tests {
+		if inside := frustum.ContainsPoint(test.point); inside != test.inside {
+			t.Errorf("%s: contains %v, want %v", test.name, inside, test.inside)
+		}
+	}
+
+	spheres := []struct {
+		name    string
+		sphere  Sphere
+		visible bool
+	}{
+		{"around the center", Sphere{mgl.Vec3{0, 0, 0}, 1}, true},
+		{"reaching in from the side", Sphere{mgl.Vec3{6, 0, 0}, 1}, true},
+		{"entirely to the side", Sphere{mgl.Vec3{8, 0, 0}, 1}, false},
+		{"behind the camera", Sphere{mgl.Vec3{0, 0, 10}, 1}, false},
+	}
+	for _, test := range spheres {
+		if visible := frustum.IntersectsSphere(test.sphere); visible != test.visible {
+			t.Errorf("sphere %s: intersects %v, want %v", test.name, visible, test.visible)
+		}
+	}
+
+	boxes := []struct {
+		name    string
+		box     AABB
+		visible bool
+	}{
+		{"around the center", AABB{mgl.Vec3{-1, -1, -1}, mgl.Vec3{1, 1, 1}}, true},
+		{"reaching in from the side", AABB{mgl.Vec3{4, -1, -1}, mgl.Vec3{6, 1, 1}}, true},
+		{"entirely to the side", AABB{mgl.Vec3{7, -1, -1}, mgl.Vec3{9, 1, 1}}, false},
+		{"beyond the far plane", AABB{mgl.Vec3{-1, -1, -20}, mgl.Vec3{1, 1, -16}}, false},
+		{"empty", BoundsOfPoints(nil), false},
+	}
+	for _, test := range boxes {
+		if visible := frustum.IntersectsBox(test.box); visible != test.visible {
+			t.Errorf("box %s: intersects %v, want %v", test.name, visible, test.visible)
+		}
+	}
+}
+
+func TestCuller(t *testing.T) {
+	c := NewCuller()
+	c.SetViewProjection(mgl.Ident4(), mgl.Ortho(-1, 1, -1, 1, -1, 1))
+	box := AABB{mgl.Vec3{-0.5, -0.5, -0.5}, mgl.Vec3{0.5, 0.5, 0.5}}
+
+	if !c.Visible(box, mgl.Ident4()) {
+		t.Errorf("box at the origin culled")
+	}
+	if c.Visible(box, mgl.Translate3D(5, 0, 0)) {
+		t.Errorf("box to the side not culled")
+	}
+	c.Enabled = false
+	if !c.Visible(box, mgl.Translate3D(5, 0, 0)) {
+		t.Errorf("box culled while culling is disabled")
+	}
+
+	c.EndFrame()
+	if c.LastFrameTested != 3 || c.LastFrameCulled != 1 {
+		t.Errorf("%d of %d culled, want 1 of 3", c.LastFrameCulled, c.LastFrameTested)
+	}
+	if c.Tested != 0 || c.Culled != 0 {
+		t.Errorf("counters %d and %d after EndFrame, want 0", c.Tested, c.Culled)
+	}
+}
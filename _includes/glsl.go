@@ -0,0 +1,150 @@
+package _includes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gl/gl"
+)
+
+type GLSLTarget int
+
+const (
+	GLSL130 GLSLTarget = iota
+	GLSL330Core
+	GLSLES300
+)
+
+// version every program is adapted to, NewApp detects it from the context
+var GLSL = GLSL130
+
+var glslVersions = map[GLSLTarget]string{
+	GLSL130:     "#version 130",
+	GLSL330Core: "#version 330 core",
+	GLSLES300:   "#version 300 es",
+}
+
+func (target GLSLTarget) String() string {
+	return glslVersions[target]
+}
+
+// picks the GLSL version to use for the current context
+func DetectGLSL() GLSLTarget {
+	version := gl.GetString(gl.VERSION)
+	if strings.HasPrefix(version, "OpenGL ES") {
+		return GLSLES300
+	}
+
+	var major, minor int
+	fmt.Sscanf(version, "%d.%d", &major, &minor)
+	if major > 3 || major == 3 && minor >= 3 {
+		if getInteger(gl.CONTEXT_PROFILE_MASK)&gl.CONTEXT_CORE_PROFILE_BIT != 0 {
+			return GLSL330Core
+		}
+	}
+	return GLSL130
+}
+
+var (
+	versionPattern   = regexp.MustCompile(`^\s*#version\s+\d+.*$`)
+	extensionPattern = regexp.MustCompile(`^\s*#extension\s+(\w+)`)
+	attributePattern = regexp.MustCompile(`\battribute\b`)
+	varyingPattern   = regexp.MustCompile(`\bvarying\b`)
+	texturePattern   = regexp.MustCompile(`\b(texture2D|textureCube)\s*\(`)
+	shadowPattern    = regexp.MustCompile(`\bshadow2D\s*\(`)
+	fragColorPattern = regexp.MustCompile(`\bgl_FragColor\b`)
+	samplerPattern   = regexp.MustCompile(`\bsampler\w*\s+texture\b`)
+)
+
+// extensions that are part of GLSL 3.30 and ES 3.00
+var coreExtensions = map[string]bool{
+	"GL_ARB_uniform_buffer_object": true,
+}
+
+// rewrites a #version 130 shader source for target: attribute and varying become in and out,
+// texture2D becomes texture, gl_FragColor becomes a declared output and ES gets precision qualifiers.
+// Sources must not name anything texture and, for ES, must not rely on implicit int to float conversions.
+func AdaptShader(source string, shaderType gl.GLenum, target GLSLTarget) string {
+	if target == GLSL130 {
+		return source
+	}
+	if samplerPattern.MatchString(source) {
+		panic("a sampler named texture hides the texture() function of newer GLSL versions!")
+	}
+
+	var header []string
+	if target == GLSLES300 {
+		header = append(header, "precision highp float;", "precision highp int;")
+		if strings.Contains(source, "sampler2DShadow") {
+			header = append(header, "precision highp sampler2DShadow;")
+		}
+	}
+	if shaderType == gl.FRAGMENT_SHADER && fragColorPattern.MatchString(source) {
+		header = append(header, "out vec4 fragmentColor;")
+		source = fragColorPattern.ReplaceAllString(source, "fragmentColor")
+	}
+
+	if shaderType == gl.VERTEX_SHADER {
+		source = attributePattern.ReplaceAllString(source, "in")
+		source = varyingPattern.ReplaceAllString(source, "out")
+	} else {
+		source = varyingPattern.ReplaceAllString(source, "in")
+	}
+	source = texturePattern.ReplaceAllString(source, "texture(")
+	source = adaptShadowLookups(source)
+
+	// the header goes after the directives, which have to come first
+	var lines []string
+	inserted := false
+	for _, line := range strings.Split(source, "\n") {
+		if !inserted {
+			if versionPattern.MatchString(line) {
+				lines = append(lines, target.String())
+				continue
+			}
+			if match := extensionPattern.FindStringSubmatch(line); match != nil {
+				if !coreExtensions[match[1]] {
+					lines = append(lines, line)
+				}
+				continue
+			}
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, header...)
+				inserted = true
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// shadow2D returns a vec4 with alpha 1 while texture on a shadow sampler returns a float,
+// so the call including its arguments gets wrapped to keep the vec4
+func adaptShadowLookups(source string) string {
+	for {
+		match := shadowPattern.FindStringIndex(source)
+		if match == nil {
+			return source
+		}
+
+		// find the parenthesis closing the call
+		depth := 1
+		end := match[1]
+		for ; end < len(source) && depth > 0; end++ {
+			switch source[end] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+		if depth > 0 {
+			panic("unbalanced parentheses in shadow2D call!")
+		}
+
+		arguments := source[match[1] : end-1]
+		source = source[:match[0]] + "vec4(vec3(texture(" + arguments + ")), 1.0)" + source[end:]
+	}
+}
@@ -0,0 +1,31 @@
+package _includes
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// float comparisons for the math tests, with room for rounding
+const epsilon = 1e-4
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < epsilon
+}
+
+func nearVec3(a, b mgl.Vec3) bool {
+	return near(a[0], b[0]) && near(a[1], b[1]) && near(a[2], b[2])
+}
+
+func nearVec4(a, b mgl.Vec4) bool {
+	return near(a[0], b[0]) && near(a[1], b[1]) && near(a[2], b[2]) && near(a[3], b[3])
+}
+
+func nearMat4(a, b mgl.Mat4) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package _includes
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+type Instance struct {
+	Model mgl.Mat4
+	Color mgl.Vec4
+	Data  mgl.Vec4
+}
+
+type Instances []Instance
+
+// attributes longer than 4 floats (mat4) take up consecutive locations, one per column
+var InstanceLayout = VertexLayout{
+	Stride: int(unsafe.Sizeof(Instance{})),
+	Attributes: []VertexAttribute{
+		{"instanceModel", 16, 0},
+		{"instanceColor", 4, unsafe.Sizeof(mgl.Mat4{})},
+		{"instanceData", 4, unsafe.Sizeof(mgl.Mat4{}) + vec4Size},
+	},
+}
+
+// per instance vertex data, advanced once per instance instead of once per vertex
+type InstanceBuffer struct {
+	Buffer   gl.Buffer
+	Layout   VertexLayout
+	Count    int
+	capacity int
+}
+
+func NewInstanceBuffer(instances Instances) *InstanceBuffer {
+	return NewCustomInstanceBuffer(InstanceLayout, instances, len(instances))
+}
+
+// instance buffer for any struct slice, layout has to describe one element of data
+func NewCustomInstanceBuffer(layout VertexLayout, data interface{}, count int) *InstanceBuffer {
+	b := &InstanceBuffer{
+		Buffer:   genBuffer(),
+		Layout:   layout,
+		Count:    count,
+		capacity: count,
+	}
+
+	if count == 0 {
+		// go-gl can't take the address of an empty slice
+		data = nil
+	}
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	gl.BufferData(gl.ARRAY_BUFFER, count*layout.Stride, data, gl.DYNAMIC_DRAW)
+	glh.OpenGLSentinel()
+
+	return b
+}
+
+// replaces all instances at once, the instance count follows the slice length
+func (b *InstanceBuffer) Update(instances Instances) {
+	b.UpdateCustom(instances, len(instances))
+}
+
+func (b *InstanceBuffer) UpdateCustom(data interface{}, count int) {
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	if count > b.capacity {
+		// grow buffer
+		b.capacity = count
+		gl.BufferData(gl.ARRAY_BUFFER, count*b.Layout.Stride, data, gl.DYNAMIC_DRAW)
+	} else if count > 0 {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, count*b.Layout.Stride, data)
+	}
+	glh.OpenGLSentinel()
+
+	b.Count = count
+}
+
+// overwrites instances starting at index first, the buffer is not grown
+func (b *InstanceBuffer) UpdateRange(first int, instances Instances) {
+	if first < 0 || first+len(instances) > b.capacity {
+		panic("instance range out of bounds!")
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	gl.BufferSubData(gl.ARRAY_BUFFER, first*b.Layout.Stride, len(instances)*b.Layout.Stride, instances)
+	glh.OpenGLSentinel()
+
+	if first+len(instances) > b.Count {
+		b.Count = first + len(instances)
+	}
+}
+
+func (b *InstanceBuffer) enableAttributes(program gl.Program) {
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	for _, attribute := range b.Layout.Attributes {
+		attrib := program.GetAttribLocation(attribute.Name)
+		if attrib < 0 {
+			continue
+		}
+
+		length, columns := attribute.Length, uint(1)
+		if length > 4 {
+			length, columns = 4, attribute.Length/4
+		}
+
+		for c := uint(0); c < columns; c++ {
+			location := attrib + gl.AttribLocation(c)
+			location.EnableArray()
+			location.AttribPointer(length, gl.FLOAT, false, b.Layout.Stride, attribute.Offset+uintptr(c)*vec4Size)
+			location.AttribDivisor(1)
+		}
+	}
+}
+
+// frees the buffer, meshes drawn with it keep a vertex array object for it until they are deleted
+func (b *InstanceBuffer) Delete() {
+	Resources.Release(b.Buffer)
+	b.Buffer = 0
+	b.Count = 0
+	b.capacity = 0
+}
@@ -0,0 +1,294 @@
+package _includes
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// has to match MAX_LIGHTS in LightingShaderSource
+const MaxLights = 8
+
+type LightType int
+
+const (
+	DirectionalLight LightType = iota
+	PointLight
+	SpotLight
+)
+
+// GLSL functions for per fragment lighting, insert right after the #version line of a fragment shader
+// and call applyLighting with world space position and normal of the fragment
+const LightingShaderSource = `
+		#define MAX_LIGHTS 8
+		#define DIRECTIONAL_LIGHT 0
+		#define POINT_LIGHT 1
+		#define SPOT_LIGHT 2
+
+		struct Light {
+			int kind;
+			vec3 position;
+			vec3 direction;
+			vec3 color;
+			vec3 attenuation;
+			vec2 cone;
+			int shadow;
+		};
+
+		uniform Light lights[MAX_LIGHTS];
+		uniform int lightCount;
+		uniform vec3 ambient;
+		uniform vec3 cameraPosition;
+		uniform float specularStrength;
+		uniform float shininess;
+		uniform int blinnPhong;
+
+		uniform sampler2D shadowMap;
+		uniform mat4 lightSpace;
+		uniform float shadowBias;
+		uniform int shadowPCF;
+
+		// fraction of light reaching position, averaged over (2 * shadowPCF + 1)^2 samples
+		float shadowFactor(vec3 position, vec3 normal, vec3 lightDirection) {
+			vec4 projected = lightSpace * vec4(position, 1.0);
+			vec3 coords = projected.xyz / projected.w * 0.5 + 0.5;
+			if (coords.z > 1.0) {
+				return 1.0;
+			}
+
+			// slope scaled bias against shadow acne
+			float bias = max(shadowBias * (1.0 - dot(normal, lightDirection)), shadowBias * 0.1);
+			vec2 texel = 1.0 / vec2(textureSize(shadowMap, 0));
+
+			float lit = 0.0;
+			for (int x = -shadowPCF; x <= shadowPCF; x++) {
+				for (int y = -shadowPCF; y <= shadowPCF; y++) {
+					float depth = texture2D(shadowMap, coords.xy + vec2(x, y) * texel).r;
+					lit += coords.z - bias > depth ? 0.0 : 1.0;
+				}
+			}
+			float samples = float((2 * shadowPCF + 1) * (2 * shadowPCF + 1));
+			return lit / samples;
+		}
+
+		vec3 applyLight(Light light, vec3 position, vec3 normal, vec3 viewDirection, vec3 albedo) {
+			vec3 lightDirection;
+			float attenuation = 1.0;
+			if (light.kind == DIRECTIONAL_LIGHT) {
+				lightDirection = normalize(-light.direction);
+			} else {
+				vec3 toLight = light.position - position;
+				float dist = length(toLight);
+				lightDirection = toLight / dist;
+				attenuation = 1.0 / (light.attenuation.x + light.attenuation.y * dist + light.attenuation.z * dist * dist);
+
+				if (light.kind == SPOT_LIGHT) {
+					// cone holds the cosines of the inner and outer angle
+					float theta = dot(lightDirection, normalize(-light.direction));
+					attenuation *= clamp((theta - light.cone.y) / max(light.cone.x - light.cone.y, 0.0001), 0.0, 1.0);
+				}
+			}
+
+			if (light.shadow != 0) {
+				attenuation *= shadowFactor(position, normal, lightDirection);
+			}
+
+			float diffuse = max(dot(normal, lightDirection), 0.0);
+
+			float specular = 0.0;
+			if (diffuse > 0.0) {
+				if (blinnPhong != 0) {
+					vec3 halfway = normalize(lightDirection + viewDirection);
+					specular = pow(max(dot(normal, halfway), 0.0), shininess);
+				} else {
+					vec3 reflected = reflect(-lightDirection, normal);
+					specular = pow(max(dot(viewDirection, reflected), 0.0), shininess);
+				}
+			}
+
+			return attenuation * light.color * (diffuse * albedo + specular * specularStrength);
+		}
+
+		vec3 applyLighting(vec3 position, vec3 normal, vec3 albedo) {
+			vec3 n = normalize(normal);
+			vec3 viewDirection = normalize(cameraPosition - position);
+
+			vec3 result = ambient * albedo;
+			for (int i = 0; i < MAX_LIGHTS; i++) {
+				if (i >= lightCount) {
+					break;
+				}
+				result += applyLight(lights[i], position, n, viewDirection, albedo);
+			}
+			return result;
+		}
+`
+
+type Light struct {
+	Type      LightType
+	Position  mgl.Vec3
+	Direction mgl.Vec3
+	Color     mgl.Vec3
+	Intensity float32
+	// constant, linear and quadratic attenuation factors
+	Attenuation mgl.Vec3
+	// inner and outer cone angles in radians, spot lights only
+	InnerCone float32
+	OuterCone float32
+}
+
+func NewDirectionalLight(direction, color mgl.Vec3, intensity float32) *Light {
+	return &Light{
+		Type:        DirectionalLight,
+		Direction:   direction.Normalize(),
+		Color:       color,
+		Intensity:   intensity,
+		Attenuation: mgl.Vec3{1, 0, 0},
+	}
+}
+
+func NewPointLight(position, color mgl.Vec3, intensity float32) *Light {
+	return &Light{
+		Type:        PointLight,
+		Position:    position,
+		Color:       color,
+		Intensity:   intensity,
+		Attenuation: mgl.Vec3{1, 0.09, 0.032},
+	}
+}
+
+func NewSpotLight(position, direction, color mgl.Vec3, intensity, innerCone, outerCone float32) *Light {
+	return &Light{
+		Type:        SpotLight,
+		Position:    position,
+		Direction:   direction.Normalize(),
+		Color:       color,
+		Intensity:   intensity,
+		Attenuation: mgl.Vec3{1, 0.09, 0.032},
+		InnerCone:   innerCone,
+		OuterCone:   outerCone,
+	}
+}
+
+type lightLocations struct {
+	Type        gl.UniformLocation
+	Position    gl.UniformLocation
+	Direction   gl.UniformLocation
+	Color       gl.UniformLocation
+	Attenuation gl.UniformLocation
+	Cone        gl.UniformLocation
+	Shadow      gl.UniformLocation
+}
+
+type Lighting struct {
+	Lights           []*Light
+	Ambient          mgl.Vec3
+	SpecularStrength float32
+	Shininess        float32
+	BlinnPhong       bool
+	Shadow           *ShadowMap
+	lights           [MaxLights]lightLocations
+	lightCount       gl.UniformLocation
+	ambient          gl.UniformLocation
+	cameraPosition   gl.UniformLocation
+	specularStrength gl.UniformLocation
+	shininess        gl.UniformLocation
+	blinnPhong       gl.UniformLocation
+	shadowMap        gl.UniformLocation
+	lightSpace       gl.UniformLocation
+	shadowBias       gl.UniformLocation
+	shadowPCF        gl.UniformLocation
+}
+
+// looks up the uniforms declared by LightingShaderSource in the given program
+func NewLighting(program gl.Program) *Lighting {
+	l := &Lighting{
+		Ambient:          mgl.Vec3{0.1, 0.1, 0.1},
+		SpecularStrength: 0.5,
+		Shininess:        32,
+		BlinnPhong:       true,
+	}
+
+	for i := range l.lights {
+		name := fmt.Sprintf("lights[%d].", i)
+		l.lights[i] = lightLocations{
+			Type:        program.GetUniformLocation(name + "kind"),
+			Position:    program.GetUniformLocation(name + "position"),
+			Direction:   program.GetUniformLocation(name + "direction"),
+			Color:       program.GetUniformLocation(name + "color"),
+			Attenuation: program.GetUniformLocation(name + "attenuation"),
+			Cone:        program.GetUniformLocation(name + "cone"),
+			Shadow:      program.GetUniformLocation(name + "shadow"),
+		}
+	}
+	l.lightCount = program.GetUniformLocation("lightCount")
+	l.ambient = program.GetUniformLocation("ambient")
+	l.cameraPosition = program.GetUniformLocation("cameraPosition")
+	l.specularStrength = program.GetUniformLocation("specularStrength")
+	l.shininess = program.GetUniformLocation("shininess")
+	l.blinnPhong = program.GetUniformLocation("blinnPhong")
+	l.shadowMap = program.GetUniformLocation("shadowMap")
+	l.lightSpace = program.GetUniformLocation("lightSpace")
+	l.shadowBias = program.GetUniformLocation("shadowBias")
+	l.shadowPCF = program.GetUniformLocation("shadowPCF")
+	glh.OpenGLSentinel()
+
+	return l
+}
+
+func (l *Lighting) Add(light *Light) *Light {
+	if len(l.Lights) >= MaxLights {
+		panic("too many lights!")
+	}
+	l.Lights = append(l.Lights, light)
+	return light
+}
+
+// uploads all lights, the program has to be in use
+func (l *Lighting) Apply(cameraPosition mgl.Vec3) {
+	count := len(l.Lights)
+	if count > MaxLights {
+		count = MaxLights
+	}
+
+	for i, light := range l.Lights[:count] {
+		loc := l.lights[i]
+		color := light.Color.Mul(light.Intensity)
+
+		loc.Type.Uniform1i(int(light.Type))
+		loc.Position.Uniform3f(light.Position[0], light.Position[1], light.Position[2])
+		loc.Direction.Uniform3f(light.Direction[0], light.Direction[1], light.Direction[2])
+		loc.Color.Uniform3f(color[0], color[1], color[2])
+		loc.Attenuation.Uniform3f(light.Attenuation[0], light.Attenuation[1], light.Attenuation[2])
+		loc.Cone.Uniform2f(float32(math.Cos(float64(light.InnerCone))), float32(math.Cos(float64(light.OuterCone))))
+		if l.Shadow != nil && l.Shadow.Light == light {
+			loc.Shadow.Uniform1i(1)
+		} else {
+			loc.Shadow.Uniform1i(0)
+		}
+	}
+
+	l.lightCount.Uniform1i(count)
+	l.ambient.Uniform3f(l.Ambient[0], l.Ambient[1], l.Ambient[2])
+	l.cameraPosition.Uniform3f(cameraPosition[0], cameraPosition[1], cameraPosition[2])
+	l.specularStrength.Uniform1f(l.SpecularStrength)
+	l.shininess.Uniform1f(l.Shininess)
+	if l.BlinnPhong {
+		l.blinnPhong.Uniform1i(1)
+	} else {
+		l.blinnPhong.Uniform1i(0)
+	}
+
+	if l.Shadow != nil {
+		State.BindTextureUnit(ShadowTextureUnit, gl.TEXTURE_2D, l.Shadow.Texture)
+		State.ActiveTexture(0)
+
+		l.shadowMap.Uniform1i(ShadowTextureUnit)
+		l.lightSpace.UniformMatrix4fv(false, l.Shadow.LightSpace)
+		l.shadowBias.Uniform1f(l.Shadow.Bias)
+		l.shadowPCF.Uniform1i(l.Shadow.PCF)
+	}
+}
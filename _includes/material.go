@@ -0,0 +1,148 @@
+package _includes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+type TextureBinding struct {
+	Name    string
+	Texture gl.Texture
+	Target  gl.GLenum
+}
+
+// a program together with its uniform values, textures and render state,
+// any number of materials can share one program and be drawn with any Mesh
+type Material struct {
+	Program   gl.Program
+	Uniforms  map[string]interface{}
+	Textures  []TextureBinding
+	State     RenderState
+	locations map[string]gl.UniformLocation
+}
+
+func NewMaterial(vertexShaderSource, fragmentShaderSource string) *Material {
+	program := newProgram(vertexShaderSource, fragmentShaderSource)
+	glh.OpenGLSentinel()
+
+	return NewProgramMaterial(program)
+}
+
+// the render state starts out as the one of the current App, so App.RenderState overrides carry over
+func NewProgramMaterial(program gl.Program) *Material {
+	return &Material{
+		Program:   program,
+		Uniforms:  make(map[string]interface{}),
+		State:     BaseRenderState(),
+		locations: make(map[string]gl.UniformLocation),
+	}
+}
+
+// returns a copy sharing the same program, uniforms and textures can then be changed independently
+func (m *Material) Clone() *Material {
+	clone := &Material{
+		Program:   m.Program,
+		Uniforms:  make(map[string]interface{}, len(m.Uniforms)),
+		Textures:  make([]TextureBinding, len(m.Textures)),
+		State:     m.State,
+		locations: m.locations,
+	}
+	for name, value := range m.Uniforms {
+		clone.Uniforms[name] = value
+	}
+	copy(clone.Textures, m.Textures)
+
+	return clone
+}
+
+// frees the program, which is shared with all clones of the material,
+// textures aren't owned by the material and have to be released on their own
+func (m *Material) Delete() {
+	Resources.Release(m.Program)
+	m.Program = 0
+}
+
+func (m *Material) Uniform(name string) gl.UniformLocation {
+	location, ok := m.locations[name]
+	if !ok {
+		location = m.Program.GetUniformLocation(name)
+		m.locations[name] = location
+	}
+	return location
+}
+
+// stores a uniform value, it is sent to the program each time the material is used
+func (m *Material) Set(name string, value interface{}) {
+	m.Uniforms[name] = value
+}
+
+// binds texture to the sampler uniform name, every texture gets its own texture unit
+func (m *Material) SetTexture(name string, texture gl.Texture) {
+	for i := range m.Textures {
+		if m.Textures[i].Name == name {
+			m.Textures[i].Texture = texture
+			return
+		}
+	}
+	m.Textures = append(m.Textures, TextureBinding{name, texture, gl.TEXTURE_2D})
+}
+
+func (m *Material) Use() {
+	State.UseProgram(m.Program)
+	m.State.Apply()
+
+	for name, value := range m.Uniforms {
+		m.SetUniform(name, value)
+	}
+
+	for unit, binding := range m.Textures {
+		State.BindTextureUnit(unit, binding.Target, binding.Texture)
+		m.Uniform(binding.Name).Uniform1i(unit)
+	}
+	State.ActiveTexture(0)
+}
+
+func (m *Material) Unuse() {
+	BaseRenderState().Apply()
+	if State.LazyUnbind {
+		return
+	}
+
+	for unit, binding := range m.Textures {
+		State.BindTextureUnit(unit, binding.Target, 0)
+	}
+	State.ActiveTexture(0)
+	State.UseProgram(0)
+}
+
+// sends a single value to the program right away, the program has to be in use
+func (m *Material) SetUniform(name string, value interface{}) {
+	location := m.Uniform(name)
+	switch v := value.(type) {
+	case bool:
+		if v {
+			location.Uniform1i(1)
+		} else {
+			location.Uniform1i(0)
+		}
+	case int:
+		location.Uniform1i(v)
+	case float32:
+		location.Uniform1f(v)
+	case mgl.Vec2:
+		location.Uniform2f(v[0], v[1])
+	case mgl.Vec3:
+		location.Uniform3f(v[0], v[1], v[2])
+	case mgl.Vec4:
+		location.Uniform4f(v[0], v[1], v[2], v[3])
+	case mgl.Mat3:
+		location.UniformMatrix3fv(false, v)
+	case mgl.Mat4:
+		location.UniformMatrix4fv(false, v)
+	default:
+		panic(fmt.Sprintf("unsupported uniform type %T for %q!", value, name))
+	}
+}
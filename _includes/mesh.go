@@ -0,0 +1,443 @@
+package _includes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// named range of the mesh, counted in the indices (or vertices without indices) the mesh was created with
+type SubMesh struct {
+	Name  string
+	First int
+	Count int
+}
+
+// vertex and index buffers plus how to draw them, independent of any program,
+// a vertex array object is created lazily for every program the mesh is drawn with
+type Mesh struct {
+	VertexBuffer  gl.Buffer
+	ElementBuffer gl.Buffer
+	Layout        VertexLayout
+	Mode          gl.GLenum
+	VertexCount   int
+	// quads are uploaded as triangles, IndexCount is the number of triangle indices uploaded
+	// while ranges keep counting the quad indices passed in, see Count
+	IndexCount int
+	// gl.UNSIGNED_BYTE, gl.UNSIGNED_SHORT or gl.UNSIGNED_INT, 0 without indices
+	IndexType gl.GLenum
+	SubMeshes []SubMesh
+	Stream    *StreamBuffer
+	// bounds of the vertices in model space, see Culler. Stream meshes update them on every write,
+	// vertices changed with raw GL calls leave them stale
+	Bounds       AABB
+	Sphere       Sphere
+	vertexArrays map[vertexArrayKey]gl.VertexArray
+	// copies for picking, stream meshes keep the positions of the last write
+	positions []mgl.Vec3
+	indices   []uint32
+	// gl.QUADS or gl.QUAD_STRIP and the number of their indices for triangulated meshes
+	sourceMode  gl.GLenum
+	sourceCount int
+}
+
+// vertex array objects aren't shared between contexts
+type vertexArrayKey struct {
+	context   int
+	program   gl.Program
+	instances *InstanceBuffer
+}
+
+// indices may be nil, []uint8, []uint16, []uint32 or []int32
+func NewMesh(vertices, indices interface{}, mode gl.GLenum) *Mesh {
+	return newMesh(vertices, indices, mode, gl.STATIC_DRAW)
+}
+
+func NewDynamicMesh(vertices, indices interface{}, mode gl.GLenum) *Mesh {
+	return newMesh(vertices, indices, mode, gl.DYNAMIC_DRAW)
+}
+
+// mesh whose vertices are replaced every frame through mesh.Stream,
+// without indices the number of vertices drawn follows the last write
+func NewStreamMesh(vertices, indices interface{}, mode gl.GLenum) *Mesh {
+	// make sure no vertex array object picks up our element buffer
+	State.BindVertexArray(0)
+
+	stream := NewStreamBuffer(vertices)
+	mesh := &Mesh{
+		VertexBuffer: stream.Buffer,
+		Layout:       stream.Layout,
+		Mode:         mode,
+		VertexCount:  stream.Count,
+		Stream:       stream,
+		vertexArrays: make(map[vertexArrayKey]gl.VertexArray),
+	}
+	// every Write and UpdateRange of the stream refreshes the copy
+	stream.positions = PositionsOf(vertices)
+	stream.positionsChanged = mesh.setPositions
+	mesh.setPositions(stream.positions)
+	mesh.setIndices(indices)
+	glh.OpenGLSentinel()
+
+	return mesh
+}
+
+func newMesh(vertices, indices interface{}, mode gl.GLenum, usage gl.GLenum) *Mesh {
+	layout, count := LayoutOf(vertices)
+
+	mesh := &Mesh{
+		Layout:       layout,
+		Mode:         mode,
+		VertexCount:  count,
+		vertexArrays: make(map[vertexArrayKey]gl.VertexArray),
+	}
+	mesh.setPositions(PositionsOf(vertices))
+
+	// make sure no vertex array object picks up our element buffer
+	State.BindVertexArray(0)
+
+	// create vertex buffer object
+	mesh.VertexBuffer = genBuffer()
+	State.BindBuffer(gl.ARRAY_BUFFER, mesh.VertexBuffer)
+	gl.BufferData(gl.ARRAY_BUFFER, count*layout.Stride, vertices, usage)
+
+	mesh.setIndices(indices)
+	glh.OpenGLSentinel()
+
+	return mesh
+}
+
+func (mesh *Mesh) setPositions(positions []mgl.Vec3) {
+	mesh.positions = positions
+	mesh.Bounds = BoundsOfPoints(positions)
+	mesh.Sphere = SphereOfPoints(positions)
+}
+
+func (mesh *Mesh) setIndices(indices interface{}) {
+	// core profiles have no quads, draw them as triangles with either profile
+	if mesh.Mode == gl.QUADS || mesh.Mode == gl.QUAD_STRIP {
+		if indices == nil {
+			indices = sequence(mesh.VertexCount)
+		}
+		mesh.sourceMode = mesh.Mode
+		indices, mesh.Mode = Triangulate(indexSlice(indices), mesh.Mode)
+	}
+
+	indexType, count := indexTypeOf(indices)
+	if count == 0 {
+		return
+	}
+	if max := maxIndex(indices); max >= mesh.VertexCount {
+		panic(fmt.Sprintf("index %d out of range of %d vertices!", max, mesh.VertexCount))
+	}
+
+	// create element array buffer object
+	mesh.ElementBuffer = genBuffer()
+	State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, mesh.ElementBuffer)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, count*int(glh.Sizeof(indexType)), indices, gl.STATIC_DRAW)
+
+	mesh.IndexType = indexType
+	mesh.IndexCount = count
+	switch mesh.sourceMode {
+	case gl.QUADS:
+		mesh.sourceCount = count / 6 * 4
+	case gl.QUAD_STRIP:
+		mesh.sourceCount = count/6*2 + 2
+	}
+	mesh.indices = append([]uint32(nil), indexSlice(indices)...)
+}
+
+func indexTypeOf(indices interface{}) (gl.GLenum, int) {
+	switch i := indices.(type) {
+	case nil:
+		return 0, 0
+	case []uint8:
+		return gl.UNSIGNED_BYTE, len(i)
+	case []uint16:
+		return gl.UNSIGNED_SHORT, len(i)
+	case []uint32:
+		return gl.UNSIGNED_INT, len(i)
+	case []int32:
+		return gl.UNSIGNED_INT, len(i)
+	}
+	panic("unknown index type provided!")
+}
+
+func sequence(count int) []uint32 {
+	indices := make([]uint32, count)
+	for i := range indices {
+		indices[i] = uint32(i)
+	}
+	return indices
+}
+
+func indexSlice(indices interface{}) []uint32 {
+	switch i := indices.(type) {
+	case []uint8:
+		s := make([]uint32, len(i))
+		for n, index := range i {
+			s[n] = uint32(index)
+		}
+		return s
+	case []uint16:
+		s := make([]uint32, len(i))
+		for n, index := range i {
+			s[n] = uint32(index)
+		}
+		return s
+	case []uint32:
+		return i
+	case []int32:
+		s := make([]uint32, len(i))
+		for n, index := range i {
+			s[n] = uint32(index)
+		}
+		return s
+	}
+	panic("unknown index type provided!")
+}
+
+// splits every quad of a gl.QUADS or gl.QUAD_STRIP index list into two triangles with the same winding,
+// other modes are returned unchanged
+func Triangulate(indices []uint32, mode gl.GLenum) ([]uint32, gl.GLenum) {
+	var quads [][4]uint32
+	switch mode {
+	case gl.QUADS:
+		for i := 0; i+3 < len(indices); i += 4 {
+			quads = append(quads, [4]uint32{indices[i], indices[i+1], indices[i+2], indices[i+3]})
+		}
+	case gl.QUAD_STRIP:
+		for i := 0; i+3 < len(indices); i += 2 {
+			quads = append(quads, [4]uint32{indices[i], indices[i+1], indices[i+3], indices[i+2]})
+		}
+	default:
+		return indices, mode
+	}
+
+	triangles := make([]uint32, 0, len(quads)*6)
+	for _, q := range quads {
+		triangles = append(triangles, q[0], q[1], q[2], q[2], q[3], q[0])
+	}
+	return triangles, gl.TRIANGLES
+}
+
+func maxIndex(indices interface{}) int {
+	max := 0
+	switch i := indices.(type) {
+	case []uint8:
+		for _, index := range i {
+			if int(index) > max {
+				max = int(index)
+			}
+		}
+	case []uint16:
+		for _, index := range i {
+			if int(index) > max {
+				max = int(index)
+			}
+		}
+	case []uint32:
+		for _, index := range i {
+			if int(index) > max {
+				max = int(index)
+			}
+		}
+	case []int32:
+		// read as unsigned by GL
+		for _, index := range i {
+			if int(uint32(index)) > max {
+				max = int(uint32(index))
+			}
+		}
+	}
+	return max
+}
+
+func (mesh *Mesh) Indexed() bool {
+	return mesh.IndexType != 0
+}
+
+// number of indices for indexed meshes and of vertices otherwise, what ranges are counted in.
+// For quads these are the quad indices, whole quads only.
+func (mesh *Mesh) Count() int {
+	if mesh.sourceMode != 0 {
+		return mesh.sourceCount
+	}
+	if mesh.Indexed() {
+		return mesh.IndexCount
+	}
+	if mesh.Stream != nil {
+		return mesh.Stream.Count
+	}
+	return mesh.VertexCount
+}
+
+// records a named range to be drawn with DrawSubMesh
+func (mesh *Mesh) AddSubMesh(name string, first, count int) {
+	if first < 0 || count < 0 || first+count > mesh.Count() {
+		panic(fmt.Sprintf("sub-mesh %q out of bounds!", name))
+	}
+	if _, _, err := mesh.uploadedRange(first, count); err != nil {
+		panic(fmt.Sprintf("sub-mesh %q: %v!", name, err))
+	}
+	mesh.SubMeshes = append(mesh.SubMeshes, SubMesh{name, first, count})
+}
+
+// converts a range of quad indices into the triangle indices they were uploaded as
+func (mesh *Mesh) uploadedRange(first, count int) (int, int, error) {
+	switch mesh.sourceMode {
+	case gl.QUADS:
+		if first%4 != 0 || count%4 != 0 {
+			return 0, 0, fmt.Errorf("range %d+%d doesn't fall on quad boundaries", first, count)
+		}
+		return first / 4 * 6, count / 4 * 6, nil
+	case gl.QUAD_STRIP:
+		// quad k of a strip starts at index 2k, neighbours share two indices
+		if first%2 != 0 || count%2 != 0 || count == 2 {
+			return 0, 0, fmt.Errorf("range %d+%d doesn't fall on quad boundaries", first, count)
+		}
+		if count == 0 {
+			return first / 2 * 6, 0, nil
+		}
+		return first / 2 * 6, (count - 2) / 2 * 6, nil
+	}
+	return first, count, nil
+}
+
+func (mesh *Mesh) SubMesh(name string) (SubMesh, bool) {
+	for _, subMesh := range mesh.SubMeshes {
+		if subMesh.Name == name {
+			return subMesh, true
+		}
+	}
+	return SubMesh{}, false
+}
+
+func (mesh *Mesh) vertexArray(program gl.Program, instances *InstanceBuffer) gl.VertexArray {
+	key := vertexArrayKey{currentContext, program, instances}
+	if vertexArray, ok := mesh.vertexArrays[key]; ok {
+		return vertexArray
+	}
+
+	// create vertex array object with the attribute locations of this program
+	vertexArray := genVertexArray()
+	State.BindVertexArray(vertexArray)
+	State.BindBuffer(gl.ARRAY_BUFFER, mesh.VertexBuffer)
+	if mesh.Indexed() {
+		State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, mesh.ElementBuffer)
+	}
+
+	for _, attribute := range mesh.Layout.Attributes {
+		attrib := program.GetAttribLocation(attribute.Name)
+		if attrib < 0 {
+			// not used by this program
+			continue
+		}
+
+		var offset interface{}
+		if attribute.Offset != 0 {
+			offset = attribute.Offset
+		}
+		attrib.EnableArray()
+		attrib.AttribPointer(attribute.Length, gl.FLOAT, false, mesh.Layout.Stride, offset)
+	}
+
+	if instances != nil {
+		instances.enableAttributes(program)
+	}
+
+	State.BindVertexArray(0)
+	glh.OpenGLSentinel()
+
+	mesh.vertexArrays[key] = vertexArray
+	return vertexArray
+}
+
+// draws the whole mesh with a material, the material has to be in use
+func (mesh *Mesh) Draw(material *Material) error {
+	return mesh.DrawRange(material, 0, mesh.Count())
+}
+
+// draws the whole mesh unless the Culler of the current App finds it outside the view frustum,
+// model is sent to the "model" uniform of the material in use
+func (mesh *Mesh) DrawCulled(material *Material, model mgl.Mat4) error {
+	if a := contexts[currentContext]; a != nil && !a.Culling.VisibleMesh(mesh, model) {
+		return nil
+	}
+	material.SetUniform("model", model)
+	return mesh.Draw(material)
+}
+
+func (mesh *Mesh) DrawSubMesh(material *Material, name string) error {
+	subMesh, ok := mesh.SubMesh(name)
+	if !ok {
+		return fmt.Errorf("mesh has no sub-mesh %q", name)
+	}
+	return mesh.DrawRange(material, subMesh.First, subMesh.Count)
+}
+
+// draws count indices (or vertices without indices) starting at first
+func (mesh *Mesh) DrawRange(material *Material, first, count int) error {
+	return mesh.draw(material, nil, first, count)
+}
+
+// draws instances.Count copies of the mesh in a single draw call
+func (mesh *Mesh) DrawInstanced(material *Material, instances *InstanceBuffer) error {
+	if instances.Count == 0 {
+		return nil
+	}
+	return mesh.draw(material, instances, 0, mesh.Count())
+}
+
+func (mesh *Mesh) draw(material *Material, instances *InstanceBuffer, first, count int) error {
+	if first < 0 || count < 0 || first+count > mesh.Count() {
+		return fmt.Errorf("draw range %d+%d exceeds the %d elements of the mesh", first, count, mesh.Count())
+	}
+	first, count, err := mesh.uploadedRange(first, count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	vertexArray := mesh.vertexArray(material.Program, instances)
+	State.BindVertexArray(vertexArray)
+
+	if mesh.Indexed() {
+		offset := uintptr(first) * uintptr(glh.Sizeof(mesh.IndexType))
+		if instances != nil {
+			gl.DrawElementsInstanced(mesh.Mode, count, mesh.IndexType, offset, instances.Count)
+		} else {
+			gl.DrawElements(mesh.Mode, count, mesh.IndexType, offset)
+		}
+	} else {
+		if instances != nil {
+			gl.DrawArraysInstanced(mesh.Mode, first, count, instances.Count)
+		} else {
+			gl.DrawArrays(mesh.Mode, first, count)
+		}
+	}
+
+	if !State.LazyUnbind {
+		State.BindVertexArray(0)
+	}
+	return nil
+}
+
+// frees the buffers and every vertex array object created for the mesh
+func (mesh *Mesh) Delete() {
+	for key, vertexArray := range mesh.vertexArrays {
+		Resources.ReleaseIn(key.context, vertexArray)
+		delete(mesh.vertexArrays, key)
+	}
+	Resources.Release(mesh.VertexBuffer)
+	Resources.Release(mesh.ElementBuffer)
+	mesh.VertexBuffer = 0
+	mesh.ElementBuffer = 0
+	mesh.VertexCount = 0
+	mesh.IndexCount = 0
+	mesh.SubMeshes = nil
+}
@@ -0,0 +1,50 @@
+package _includes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTriangulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		indices   []uint32
+		mode      gl.GLenum
+		triangles []uint32
+		triMode   gl.GLenum
+	}{
+		{"single quad", []uint32{0, 1, 2, 3}, gl.QUADS, []uint32{0, 1, 2, 2, 3, 0}, gl.TRIANGLES},
+		{"two quads", []uint32{0, 1, 2, 3, 4, 5, 6, 7}, gl.QUADS, []uint32{0, 1, 2, 2, 3, 0, 4, 5, 6, 6, 7, 4}, gl.TRIANGLES},
+		{"incomplete quad is dropped", []uint32{0, 1, 2, 3, 4, 5}, gl.QUADS, []uint32{0, 1, 2, 2, 3, 0}, gl.TRIANGLES},
+		// strip quads are 0 1 3 2 and 2 3 5 4, so both keep the winding of the first
+		{"quad strip", []uint32{0, 1, 2, 3, 4, 5}, gl.QUAD_STRIP, []uint32{0, 1, 3, 3, 2, 0, 2, 3, 5, 5, 4, 2}, gl.TRIANGLES},
+		{"quad strip too short", []uint32{0, 1, 2}, gl.QUAD_STRIP, []uint32{}, gl.TRIANGLES},
+		{"triangles unchanged", []uint32{0, 1, 2}, gl.TRIANGLES, []uint32{0, 1, 2}, gl.TRIANGLES},
+		{"lines unchanged", []uint32{0, 1, 1, 2}, gl.LINES, []uint32{0, 1, 1, 2}, gl.LINES},
+	}
+
+	for _, test := range tests {
+		triangles, mode := Triangulate(test.indices, test.mode)
+		if mode != test.triMode {
+			t.Errorf("%s: mode %v, want %v", test.name, mode, test.triMode)
+		}
+		if !reflect.DeepEqual(triangles, test.triangles) {
+			t.Errorf("%s: indices %v, want %v", test.name, triangles, test.triangles)
+		}
+	}
+}
+
+func TestTriangulateWinding(t *testing.T) {
+	// a counter-clockwise quad in the xy plane, both triangles have to face +z
+	positions := []mgl.Vec3{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}
+	triangles, _ := Triangulate([]uint32{0, 1, 2, 3}, gl.QUADS)
+	for i := 0; i < len(triangles); i += 3 {
+		a, b, c := positions[triangles[i]], positions[triangles[i+1]], positions[triangles[i+2]]
+		if normal := b.Sub(a).Cross(c.Sub(a)); normal[2] <= 0 {
+			t.Errorf("triangle %d faces %v, want +z", i/3, normal)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package _includes
+
+import (
+	"math"
+	"math/rand"
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const particleVertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec2 textureCoordinate;
+		in vec4 color;
+
+		varying vec2 texCoord;
+		varying vec4 tint;
+
+		uniform mat4 view;
+		uniform mat4 projection;
+
+		void main()	{
+			texCoord = textureCoordinate;
+			tint = color;
+			gl_Position = projection * view * position;
+		}
+`
+
+const particleFragmentShaderSource = `
+	#version 130
+		uniform sampler2D tex;
+
+		varying vec2 texCoord;
+		varying vec4 tint;
+
+		void main() {
+			gl_FragColor = tint * texture2D(tex, texCoord);
+		}
+`
+
+type Particle struct {
+	Position mgl.Vec3
+	Velocity mgl.Vec3
+	Color    mgl.Vec4
+	Size     float32
+	Rotation float32
+	Age      float32
+	Lifetime float32
+}
+
+// fraction of the lifetime that has passed, from 0 to 1
+func (p *Particle) Progress() float32 {
+	return p.Age / p.Lifetime
+}
+
+// emitter shapes return a spawn position relative to the emitter and a direction of travel
+type EmitterShape interface {
+	Sample(r *rand.Rand) (mgl.Vec3, mgl.Vec3)
+}
+
+type PointShape struct{}
+
+type BoxShape struct {
+	Min mgl.Vec3
+	Max mgl.Vec3
+}
+
+type SphereShape struct {
+	Radius float32
+}
+
+// cone opening around Direction, Angle is the half angle in radians
+type ConeShape struct {
+	Direction mgl.Vec3
+	Angle     float32
+	Radius    float32
+}
+
+func randomDirection(r *rand.Rand) mgl.Vec3 {
+	z := r.Float64()*2 - 1
+	phi := r.Float64() * 2 * math.Pi
+	rad := math.Sqrt(1 - z*z)
+	return mgl.Vec3{float32(rad * math.Cos(phi)), float32(rad * math.Sin(phi)), float32(z)}
+}
+
+func (s PointShape) Sample(r *rand.Rand) (mgl.Vec3, mgl.Vec3) {
+	return mgl.Vec3{}, randomDirection(r)
+}
+
+func (s BoxShape) Sample(r *rand.Rand) (mgl.Vec3, mgl.Vec3) {
+	size := s.Max.Sub(s.Min)
+	position := s.Min.Add(mgl.Vec3{size[0] * r.Float32(), size[1] * r.Float32(), size[2] * r.Float32()})
+	return position, randomDirection(r)
+}
+
+func (s SphereShape) Sample(r *rand.Rand) (mgl.Vec3, mgl.Vec3) {
+	direction := randomDirection(r)
+	// cube root for an even distribution over the volume
+	distance := s.Radius * float32(math.Cbrt(r.Float64()))
+	return direction.Mul(distance), direction
+}
+
+func (s ConeShape) Sample(r *rand.Rand) (mgl.Vec3, mgl.Vec3) {
+	axis := s.Direction.Normalize()
+	tangent := axis.Cross(mgl.Vec3{0, 1, 0})
+	if tangent.Len() < 0.001 {
+		tangent = axis.Cross(mgl.Vec3{1, 0, 0})
+	}
+	tangent = tangent.Normalize()
+	bitangent := axis.Cross(tangent)
+
+	// random direction inside the cone
+	cosAngle := 1 - r.Float64()*(1-math.Cos(float64(s.Angle)))
+	sinAngle := math.Sqrt(1 - cosAngle*cosAngle)
+	phi := r.Float64() * 2 * math.Pi
+	direction := axis.Mul(float32(cosAngle)).
+		Add(tangent.Mul(float32(sinAngle * math.Cos(phi)))).
+		Add(bitangent.Mul(float32(sinAngle * math.Sin(phi))))
+
+	// random point on the base disc
+	radius := s.Radius * float32(math.Sqrt(r.Float64()))
+	theta := r.Float64() * 2 * math.Pi
+	position := tangent.Mul(radius * float32(math.Cos(theta))).Add(bitangent.Mul(radius * float32(math.Sin(theta))))
+
+	return position, direction
+}
+
+// affectors change particles every tick, dt is in seconds
+type Affector interface {
+	Affect(p *Particle, dt float32)
+}
+
+type Gravity struct {
+	Acceleration mgl.Vec3
+}
+
+type Drag struct {
+	Coefficient float32
+}
+
+type ColorOverLifetime struct {
+	Start mgl.Vec4
+	End   mgl.Vec4
+}
+
+type SizeOverLifetime struct {
+	Start float32
+	End   float32
+}
+
+// swirls particles around an axis through Center
+type Vortex struct {
+	Center   mgl.Vec3
+	Axis     mgl.Vec3
+	Strength float32
+}
+
+func (a Gravity) Affect(p *Particle, dt float32) {
+	p.Velocity = p.Velocity.Add(a.Acceleration.Mul(dt))
+}
+
+func (a Drag) Affect(p *Particle, dt float32) {
+	p.Velocity = p.Velocity.Mul(float32(math.Max(0, float64(1-a.Coefficient*dt))))
+}
+
+func (a ColorOverLifetime) Affect(p *Particle, dt float32) {
+	t := p.Progress()
+	p.Color = a.Start.Mul(1 - t).Add(a.End.Mul(t))
+}
+
+func (a SizeOverLifetime) Affect(p *Particle, dt float32) {
+	t := p.Progress()
+	p.Size = a.Start*(1-t) + a.End*t
+}
+
+func (a Vortex) Affect(p *Particle, dt float32) {
+	axis := a.Axis.Normalize()
+	offset := p.Position.Sub(a.Center)
+	// remove the part along the axis, what's left points away from it
+	radial := offset.Sub(axis.Mul(offset.Dot(axis)))
+	p.Velocity = p.Velocity.Add(axis.Cross(radial).Mul(a.Strength * dt))
+}
+
+// seed of the next emitter, every emitter gets its own so that they don't spawn in lockstep,
+// and runs stay reproducible as long as emitters are created in the same order
+var emitterSeed int64 = 1
+
+type Emitter struct {
+	Position         mgl.Vec3
+	Shape            EmitterShape
+	Rate             float32 // particles per second
+	Speed            float32
+	SpeedVariance    float32
+	Lifetime         float32 // seconds
+	LifetimeVariance float32
+	Size             float32
+	Color            mgl.Vec4
+	MaxParticles     int
+	Affectors        []Affector
+	Particles        []Particle
+	random           *rand.Rand
+	accumulator      float32
+}
+
+func NewEmitter(position mgl.Vec3, shape EmitterShape, rate float32) *Emitter {
+	seed := emitterSeed
+	emitterSeed++
+
+	return &Emitter{
+		Position:     position,
+		Shape:        shape,
+		Rate:         rate,
+		Speed:        1,
+		Lifetime:     2,
+		Size:         0.1,
+		Color:        mgl.Vec4{1, 1, 1, 1},
+		MaxParticles: 1000,
+		random:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// restarts the random sequence of the emitter, equal seeds spawn equal particles
+func (e *Emitter) Seed(seed int64) {
+	e.random.Seed(seed)
+}
+
+func (e *Emitter) AddAffector(affector Affector) {
+	e.Affectors = append(e.Affectors, affector)
+}
+
+// emits n particles at once
+func (e *Emitter) Burst(n int) {
+	for i := 0; i < n && len(e.Particles) < e.MaxParticles; i++ {
+		e.spawn()
+	}
+}
+
+func (e *Emitter) spawn() {
+	offset, direction := e.Shape.Sample(e.random)
+	speed := e.Speed + (e.random.Float32()*2-1)*e.SpeedVariance
+	lifetime := e.Lifetime + (e.random.Float32()*2-1)*e.LifetimeVariance
+	if lifetime <= 0 {
+		lifetime = 0.001
+	}
+
+	e.Particles = append(e.Particles, Particle{
+		Position: e.Position.Add(offset),
+		Velocity: direction.Mul(speed),
+		Color:    e.Color,
+		Size:     e.Size,
+		Rotation: e.random.Float32() * 2 * math.Pi,
+		Lifetime: lifetime,
+	})
+}
+
+// advances the simulation by dt seconds
+func (e *Emitter) Update(dt float32) {
+	// continuous emission, leftovers carry over to the next tick
+	e.accumulator += e.Rate * dt
+	for ; e.accumulator >= 1; e.accumulator-- {
+		if len(e.Particles) < e.MaxParticles {
+			e.spawn()
+		}
+	}
+
+	alive := e.Particles[:0]
+	for _, p := range e.Particles {
+		p.Age += dt
+		if p.Age >= p.Lifetime {
+			continue
+		}
+
+		for _, affector := range e.Affectors {
+			affector.Affect(&p, dt)
+		}
+		p.Position = p.Position.Add(p.Velocity.Mul(dt))
+
+		alive = append(alive, p)
+	}
+	e.Particles = alive
+}
+
+// draws emitters as camera facing quads, all particles are streamed into one dynamic buffer
+type ParticleRenderer struct {
+	Shader   *Shader
+	Additive bool
+	vertices ColorTextureVertices
+	capacity int
+	// texture was passed in and is not freed by Delete
+	shared bool
+}
+
+// texture may be zero, a soft round dot is used then
+func NewParticleRenderer(texture gl.Texture) *ParticleRenderer {
+	r := &ParticleRenderer{capacity: 1024}
+
+	shader := NewShader(particleVertexShaderSource, particleFragmentShaderSource)
+	shader.SetVertexArray()
+	shader.SetVertexArrayBuffer(make(ColorTextureVertices, r.capacity*4), gl.DYNAMIC_DRAW)
+	shader.Mode = gl.TRIANGLES
+	shader.SetElementArrayBuffer(spriteIndices(r.capacity), gl.STATIC_DRAW)
+
+	shader.EnableColorTextureVertexAttributes()
+	shader.SetUniformLocations()
+
+	if texture == 0 {
+		shader.SetTexture(32, 32, particleDot(32))
+	} else {
+		shader.Texture = texture
+		r.shared = true
+	}
+
+	shader.Unuse()
+	glh.OpenGLSentinel()
+
+	r.Shader = shader
+	return r
+}
+
+func (r *ParticleRenderer) Delete() {
+	if r.shared {
+		r.Shader.Texture = 0
+	}
+	r.Shader.Delete()
+}
+
+func particleDot(size int) *[]mgl.Vec4 {
+	data := make([]mgl.Vec4, 0, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := (float64(x)+0.5)/float64(size)*2 - 1
+			dy := (float64(y)+0.5)/float64(size)*2 - 1
+			alpha := math.Max(0, 1-math.Sqrt(dx*dx+dy*dy))
+			data = append(data, mgl.Vec4{1, 1, 1, float32(alpha * alpha)})
+		}
+	}
+	return &data
+}
+
+func (r *ParticleRenderer) Draw(view, projection mgl.Mat4, emitters ...*Emitter) {
+	// camera right and up vectors are the first two rows of the view matrix
+	right := mgl.Vec3{view[0], view[4], view[8]}
+	up := mgl.Vec3{view[1], view[5], view[9]}
+
+	r.vertices = r.vertices[:0]
+	for _, e := range emitters {
+		for _, p := range e.Particles {
+			s, c := math.Sincos(float64(p.Rotation))
+			half := p.Size / 2
+			axisX := right.Mul(float32(c) * half).Add(up.Mul(float32(s) * half))
+			axisY := up.Mul(float32(c) * half).Sub(right.Mul(float32(s) * half))
+
+			r.vertices = append(r.vertices,
+				ColorTextureVertex{p.Position.Sub(axisX).Sub(axisY).Vec4(1), mgl.Vec2{0, 0}, p.Color},
+				ColorTextureVertex{p.Position.Add(axisX).Sub(axisY).Vec4(1), mgl.Vec2{1, 0}, p.Color},
+				ColorTextureVertex{p.Position.Add(axisX).Add(axisY).Vec4(1), mgl.Vec2{1, 1}, p.Color},
+				ColorTextureVertex{p.Position.Sub(axisX).Add(axisY).Vec4(1), mgl.Vec2{0, 1}, p.Color},
+			)
+		}
+	}
+
+	count := len(r.vertices) / 4
+	if count == 0 {
+		return
+	}
+
+	r.Shader.Use()
+	r.Shader.View.UniformMatrix4fv(false, view)
+	r.Shader.Projection.UniformMatrix4fv(false, projection)
+
+	size := len(r.vertices) * int(unsafe.Sizeof(ColorTextureVertex{}))
+	if count > r.capacity {
+		r.capacity = count
+		indices := spriteIndices(r.capacity)
+		gl.BufferData(gl.ARRAY_BUFFER, size, r.vertices, gl.STREAM_DRAW)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*int(glh.Sizeof(gl.UNSIGNED_INT)), indices, gl.STATIC_DRAW)
+	} else {
+		// orphan the old storage so we don't wait for the previous frame
+		gl.BufferData(gl.ARRAY_BUFFER, r.capacity*4*int(unsafe.Sizeof(ColorTextureVertex{})), nil, gl.STREAM_DRAW)
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, r.vertices)
+	}
+
+	// particles are not sorted, so don't let them hide each other
+	base := BaseRenderState()
+	state := base
+	state.DepthWrite = false
+	if r.Additive {
+		state = state.Additive()
+	}
+	state.Apply()
+
+	gl.DrawElements(gl.TRIANGLES, count*6, gl.UNSIGNED_INT, nil)
+
+	base.Apply()
+
+	r.Shader.Unuse()
+	glh.OpenGLSentinel()
+}
@@ -0,0 +1,185 @@
+package _includes
+
+import (
+	"math"
+
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// half line from Origin along Direction, which is normalized
+type Ray struct {
+	Origin    mgl.Vec3
+	Direction mgl.Vec3
+}
+
+func (r Ray) At(t float32) mgl.Vec3 {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// ray through pixel x, y of a width by height viewport, y counted from the top like cursor positions
+func ScreenRay(x, y float32, width, height int, view, projection mgl.Mat4) Ray {
+	ndcX := 2*x/float32(width) - 1
+	ndcY := 1 - 2*y/float32(height)
+
+	// the near plane and depth 0 are in front of the camera, whatever far plane the projection has
+	inverse := projection.Mul4(view).Inv()
+	near := unproject(inverse, mgl.Vec4{ndcX, ndcY, -1, 1})
+	middle := unproject(inverse, mgl.Vec4{ndcX, ndcY, 0, 1})
+
+	return Ray{near, middle.Sub(near).Normalize()}
+}
+
+func unproject(inverse mgl.Mat4, ndc mgl.Vec4) mgl.Vec3 {
+	p := inverse.Mul4x1(ndc)
+	return mgl.Vec3{p[0] / p[3], p[1] / p[3], p[2] / p[3]}
+}
+
+// ray from the camera through the mouse cursor, in world space for the view and projection the App draws with
+func (a *App) CursorRay(view, projection mgl.Mat4) Ray {
+	x, y := a.Window.GetCursorPosition()
+	// the cursor is in window coordinates, the viewport in framebuffer pixels
+	return ScreenRay(float32(x)*a.ScaleX, float32(y)*a.ScaleY, a.Width, a.Height, view, projection)
+}
+
+// Möller-Trumbore, returns the distance along the ray and the barycentric coordinates u, v of b and c.
+// Both sides of the triangle count.
+func (r Ray) IntersectTriangle(a, b, c mgl.Vec3) (t, u, v float32, ok bool) {
+	const epsilon = 1e-7
+
+	edge1 := b.Sub(a)
+	edge2 := c.Sub(a)
+	p := r.Direction.Cross(edge2)
+	det := edge1.Dot(p)
+	if math.Abs(float64(det)) < epsilon {
+		// parallel
+		return 0, 0, 0, false
+	}
+	inverse := 1 / det
+
+	s := r.Origin.Sub(a)
+	u = s.Dot(p) * inverse
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := s.Cross(edge1)
+	v = r.Direction.Dot(q) * inverse
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+
+	t = edge2.Dot(q) * inverse
+	if t < 0 {
+		return 0, 0, 0, false
+	}
+	return t, u, v, true
+}
+
+type Hit struct {
+	// in world space
+	Point    mgl.Vec3
+	Distance float32
+	// index of the triangle in draw order and the vertices it is made of
+	Triangle int
+	Vertices [3]uint32
+	// weights of the three vertices at Point, to interpolate their attributes
+	Barycentric mgl.Vec3
+}
+
+// finds the closest triangle of the mesh hit by a world space ray, model places the mesh in the world.
+// Only triangle modes can be hit, stream meshes are tested with the vertices last written to their stream.
+func (mesh *Mesh) Pick(ray Ray, model mgl.Mat4) (Hit, bool) {
+	// intersect in model space, so no vertex needs to be transformed
+	inverse := model.Inv()
+	origin := inverse.Mul4x1(ray.Origin.Vec4(1))
+	direction := inverse.Mul4x1(ray.Direction.Vec4(0))
+	local := Ray{
+		mgl.Vec3{origin[0], origin[1], origin[2]},
+		mgl.Vec3{direction[0], direction[1], direction[2]},
+	}
+
+	var hit Hit
+	found := false
+	closest := float32(math.Inf(1))
+	for i, count := 0, mesh.triangleCount(); i < count; i++ {
+		vertices := mesh.triangle(i)
+		if !mesh.hasVertices(vertices) {
+			// indices past the end of a stream that shrank
+			continue
+		}
+		a, b, c := mesh.positions[vertices[0]], mesh.positions[vertices[1]], mesh.positions[vertices[2]]
+
+		t, u, v, ok := local.IntersectTriangle(a, b, c)
+		if !ok || t >= closest {
+			continue
+		}
+		closest = t
+		found = true
+		hit.Triangle = i
+		hit.Vertices = vertices
+		hit.Barycentric = mgl.Vec3{1 - u - v, u, v}
+	}
+	if !found {
+		return Hit{}, false
+	}
+
+	// t is measured in model space, the distance has to be measured in the world
+	point := model.Mul4x1(local.At(closest).Vec4(1))
+	hit.Point = mgl.Vec3{point[0], point[1], point[2]}
+	hit.Distance = hit.Point.Sub(ray.Origin).Len()
+	return hit, true
+}
+
+func (mesh *Mesh) triangleCount() int {
+	count := len(mesh.positions)
+	if mesh.indices != nil {
+		count = len(mesh.indices)
+	}
+
+	switch mesh.Mode {
+	case gl.TRIANGLES:
+		return count / 3
+	case gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN:
+		if count < 3 {
+			return 0
+		}
+		return count - 2
+	}
+	return 0
+}
+
+// vertices of triangle i, strips alternate their winding
+func (mesh *Mesh) triangle(i int) [3]uint32 {
+	var corners [3]int
+	switch mesh.Mode {
+	case gl.TRIANGLES:
+		corners = [3]int{3 * i, 3*i + 1, 3*i + 2}
+	case gl.TRIANGLE_STRIP:
+		if i%2 == 0 {
+			corners = [3]int{i, i + 1, i + 2}
+		} else {
+			corners = [3]int{i + 1, i, i + 2}
+		}
+	case gl.TRIANGLE_FAN:
+		corners = [3]int{0, i + 1, i + 2}
+	}
+
+	var vertices [3]uint32
+	for n, corner := range corners {
+		if mesh.indices != nil {
+			vertices[n] = mesh.indices[corner]
+		} else {
+			vertices[n] = uint32(corner)
+		}
+	}
+	return vertices
+}
+
+func (mesh *Mesh) hasVertices(vertices [3]uint32) bool {
+	for _, v := range vertices {
+		if int(v) >= len(mesh.positions) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package _includes
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestIntersectTriangle(t *testing.T) {
+	// triangle in the z = 0 plane
+	a, b, c := mgl.Vec3{0, 0, 0}, mgl.Vec3{2, 0, 0}, mgl.Vec3{0, 2, 0}
+
+	tests := []struct {
+		name string
+		ray  Ray
+		hit  bool
+		t    float32
+		u, v float32
+	}{
+		{"through the middle", Ray{mgl.Vec3{0.5, 0.5, 3}, mgl.Vec3{0, 0, -1}}, true, 3, 0.25, 0.25},
+		{"from behind", Ray{mgl.Vec3{0.5, 0.5, -2}, mgl.Vec3{0, 0, 1}}, true, 2, 0.25, 0.25},
+		{"at a corner", Ray{mgl.Vec3{2, 0, 1}, mgl.Vec3{0, 0, -1}}, true, 1, 1, 0},
+		{"slanted", Ray{mgl.Vec3{0, 0, 1}, mgl.Vec3{0.5, 0.5, -1}.Normalize()}, true, 1.2247449, 0.25, 0.25},
+		{"beside", Ray{mgl.Vec3{1.5, 1.5, 3}, mgl.Vec3{0, 0, -1}}, false, 0, 0, 0},
+		{"outside of a", Ray{mgl.Vec3{-0.1, 0.5, 3}, mgl.Vec3{0, 0, -1}}, false, 0, 0, 0},
+		{"pointing away", Ray{mgl.Vec3{0.5, 0.5, 3}, mgl.Vec3{0, 0, 1}}, false, 0, 0, 0},
+		{"parallel above", Ray{mgl.Vec3{-1, 0.5, 1}, mgl.Vec3{1, 0, 0}}, false, 0, 0, 0},
+		{"parallel in the plane", Ray{mgl.Vec3{-1, 0.5, 0}, mgl.Vec3{1, 0, 0}}, false, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		distance, u, v, ok := test.ray.IntersectTriangle(a, b, c)
+		if ok != test.hit {
+			t.Errorf("%s: hit %v, want %v", test.name, ok, test.hit)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !near(distance, test.t) || !near(u, test.u) || !near(v, test.v) {
+			t.Errorf("%s: t %v u %v v %v, want t %v u %v v %v", test.name, distance, u, v, test.t, test.u, test.v)
+		}
+		if p, want := test.ray.At(distance), a.Mul(1-u-v).Add(b.Mul(u)).Add(c.Mul(v)); !nearVec3(p, want) {
+			t.Errorf("%s: ray point %v, barycentric point %v", test.name, p, want)
+		}
+	}
+}
+
+func TestScreenRay(t *testing.T) {
+	view := mgl.LookAtV(mgl.Vec3{0, 0, 5}, mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 1, 0})
+	projection := mgl.Perspective(1, 1, 0.1, 100)
+
+	// the center pixel looks straight at the target
+	ray := ScreenRay(50, 50, 100, 100, view, projection)
+	if !nearVec3(ray.Direction, mgl.Vec3{0, 0, -1}) {
+		t.Errorf("center ray direction %v, want -z", ray.Direction)
+	}
+
+	// y counts from the top, so the top row looks up
+	if ray := ScreenRay(50, 0, 100, 100, view, projection); ray.Direction[1] <= 0 {
+		t.Errorf("top ray direction %v, want it to point up", ray.Direction)
+	}
+}
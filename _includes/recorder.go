@@ -0,0 +1,198 @@
+package _includes
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+	"github.com/go-gl/glh"
+)
+
+// key OnKeyDown starts and stops recording numbered PNGs on
+var RecordKey = glfw.KeyF11
+
+type RecordFormat int
+
+const (
+	RecordPNG RecordFormat = iota
+	RecordGIF
+)
+
+// captures every frame while attached to an App. The App advances its Time by exactly Step per frame
+// while recording, so the result plays back at 1/Step frames per second however slow rendering was.
+// Pixels are read into two alternating pixel buffers and fetched one frame later, so the render loop
+// doesn't wait for the GPU, encoding happens on a background goroutine. When the encoder falls
+// behind the render loop waits for it, which costs nothing since Time doesn't follow the clock.
+type Recorder struct {
+	// directory for RecordPNG, file for RecordGIF
+	Path   string
+	Format RecordFormat
+	Step   float64
+	Frames int
+
+	width, height int
+	buffers       [2]gl.Buffer
+	pending       [2]bool
+	current       int
+	frames        chan *image.NRGBA
+	done          chan error
+}
+
+func NewRecorder(path string, format RecordFormat, fps int) *Recorder {
+	return &Recorder{
+		Path:   path,
+		Format: format,
+		Step:   1 / float64(fps),
+	}
+}
+
+// starts recording, the first frame is the next one drawn
+func (a *App) StartRecording(r *Recorder) {
+	if a.Recorder != nil {
+		panic("already recording!")
+	}
+	if r.Format == RecordPNG {
+		if err := os.MkdirAll(r.Path, 0755); err != nil {
+			panic(err)
+		}
+	}
+
+	r.width, r.height = a.Window.GetFramebufferSize()
+	size := r.width * r.height * 4
+	for i := range r.buffers {
+		r.buffers[i] = genBuffer()
+		State.BindBuffer(gl.PIXEL_PACK_BUFFER, r.buffers[i])
+		gl.BufferData(gl.PIXEL_PACK_BUFFER, size, nil, gl.STREAM_READ)
+		r.pending[i] = false
+	}
+	State.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	glh.OpenGLSentinel()
+
+	r.Frames = 0
+	r.frames = make(chan *image.NRGBA, 8)
+	r.done = make(chan error, 1)
+	go r.encode()
+
+	a.Recorder = r
+}
+
+// fetches the last frame, waits for all frames to be written and returns the first error
+func (a *App) StopRecording() error {
+	r := a.Recorder
+	if r == nil {
+		return nil
+	}
+	a.Recorder = nil
+
+	// the last frame read is still in its pixel buffer
+	r.current = 1 - r.current
+	r.fetch()
+
+	for i := range r.buffers {
+		Resources.Release(r.buffers[i])
+		r.buffers[i] = 0
+	}
+
+	close(r.frames)
+	return <-r.done
+}
+
+// starts the readback of the frame just drawn and hands the previous one to the encoder
+func (r *Recorder) capture() {
+	State.BindBuffer(gl.PIXEL_PACK_BUFFER, r.buffers[r.current])
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, r.width, r.height, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	r.pending[r.current] = true
+
+	r.current = 1 - r.current
+	r.fetch()
+	State.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	glh.OpenGLSentinel()
+}
+
+func (r *Recorder) fetch() {
+	if !r.pending[r.current] {
+		return
+	}
+	r.pending[r.current] = false
+
+	size := r.width * r.height * 4
+	State.BindBuffer(gl.PIXEL_PACK_BUFFER, r.buffers[r.current])
+	data := gl.MapBuffer(gl.PIXEL_PACK_BUFFER, gl.READ_ONLY)
+	if data == nil {
+		panic("can't map pixel buffer!")
+	}
+	pixels := make([]uint8, size)
+	copy(pixels, (*[1 << 30]uint8)(data)[:size:size])
+	gl.UnmapBuffer(gl.PIXEL_PACK_BUFFER)
+
+	// every frame advanced Time by Step, so none may be skipped
+	r.frames <- flipPixels(pixels, r.width, r.height)
+	r.Frames++
+}
+
+func (r *Recorder) encode() {
+	var err error
+	animation := &gif.GIF{}
+
+	n := 0
+	for frame := range r.frames {
+		if err != nil {
+			// keep draining, the render loop must not block
+			continue
+		}
+
+		switch r.Format {
+		case RecordPNG:
+			err = writePNG(filepath.Join(r.Path, fmt.Sprintf("frame-%05d.png", n)), frame)
+		case RecordGIF:
+			paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+			draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+			animation.Image = append(animation.Image, paletted)
+			animation.Delay = append(animation.Delay, int(r.Step*100+0.5))
+		}
+		n++
+	}
+
+	if err == nil && r.Format == RecordGIF {
+		err = writeGIF(r.Path, animation)
+	}
+	r.done <- err
+}
+
+func writeGIF(filename string, animation *gif.GIF) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := gif.EncodeAll(file, animation); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (a *App) toggleRecording() {
+	if a.Recorder == nil {
+		path := fmt.Sprintf("recording-%s", time.Now().Format("20060102-150405"))
+		a.StartRecording(NewRecorder(path, RecordPNG, 30))
+		log.Printf("recording to %v\n", path)
+		return
+	}
+
+	r := a.Recorder
+	if err := a.StopRecording(); err != nil {
+		log.Printf("can't save recording: %v\n", err)
+		return
+	}
+	log.Printf("saved %d frames to %v\n", r.Frames, r.Path)
+}
@@ -0,0 +1,191 @@
+package _includes
+
+import (
+	"github.com/go-gl/gl"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// fixed function state of a draw, start from DefaultRenderState and change what differs,
+// the zero value isn't a valid state. Apply only issues the GL calls for what changed since
+// the last applied state, see StateCache.
+type RenderState struct {
+	// used by Clear
+	ClearColor   mgl.Vec4
+	ClearDepth   float64
+	ClearStencil int
+
+	DepthTest  bool
+	DepthWrite bool
+	DepthFunc  gl.GLenum
+
+	Blend              bool
+	BlendEquation      gl.GLenum
+	BlendEquationAlpha gl.GLenum
+	BlendSrc           gl.GLenum
+	BlendDst           gl.GLenum
+	BlendSrcAlpha      gl.GLenum
+	BlendDstAlpha      gl.GLenum
+
+	CullFace  bool
+	CullMode  gl.GLenum
+	FrontFace gl.GLenum
+	// FILL, LINE or POINT, not available on ES
+	PolygonMode gl.GLenum
+	// 0 leaves the line width alone, core profiles only support 1
+	LineWidth float32
+
+	// x, y, width and height in pixels, also limits Clear
+	Scissor    bool
+	ScissorBox [4]int
+
+	Stencil          bool
+	StencilFunc      gl.GLenum
+	StencilRef       int
+	StencilReadMask  uint
+	StencilWriteMask uint
+	StencilFail      gl.GLenum
+	StencilDepthFail gl.GLenum
+	StencilPass      gl.GLenum
+}
+
+// state every App starts with and draws return to, see App.RenderState
+var DefaultRenderState = RenderState{
+	ClearColor:         mgl.Vec4{0.1, 0.1, 0.1, 1},
+	ClearDepth:         1,
+	DepthTest:          true,
+	DepthWrite:         true,
+	DepthFunc:          gl.LESS,
+	Blend:              true,
+	BlendEquation:      gl.FUNC_ADD,
+	BlendEquationAlpha: gl.FUNC_ADD,
+	BlendSrc:           gl.SRC_ALPHA,
+	BlendDst:           gl.ONE_MINUS_SRC_ALPHA,
+	BlendSrcAlpha:      gl.SRC_ALPHA,
+	BlendDstAlpha:      gl.ONE_MINUS_SRC_ALPHA,
+	CullMode:           gl.BACK,
+	FrontFace:          gl.CCW,
+	PolygonMode:        gl.FILL,
+	StencilFunc:        gl.ALWAYS,
+	StencilReadMask:    0xff,
+	StencilWriteMask:   0xff,
+	StencilFail:        gl.KEEP,
+	StencilDepthFail:   gl.KEEP,
+	StencilPass:        gl.KEEP,
+}
+
+// the state of the App whose context is current, draws restore it when they're done
+func BaseRenderState() RenderState {
+	if a := contexts[currentContext]; a != nil {
+		return a.RenderState
+	}
+	return DefaultRenderState
+}
+
+// blends by adding source to destination, for glowing particles and the like
+func (state RenderState) Additive() RenderState {
+	state.Blend = true
+	state.BlendDst = gl.ONE
+	state.BlendDstAlpha = gl.ONE
+	return state
+}
+
+func (state RenderState) Apply() {
+	State.ApplyRenderState(state)
+}
+
+// applies the state and clears color and, when they are written, depth and stencil with its clear values
+func (state RenderState) Clear() {
+	s := State
+	s.ApplyRenderState(state)
+
+	last := s.clear
+	if s.changed(last == nil || last.ClearColor != state.ClearColor) {
+		c := state.ClearColor
+		gl.ClearColor(gl.GLclampf(c[0]), gl.GLclampf(c[1]), gl.GLclampf(c[2]), gl.GLclampf(c[3]))
+	}
+	if s.changed(last == nil || last.ClearDepth != state.ClearDepth) {
+		gl.ClearDepth(gl.GLclampd(state.ClearDepth))
+	}
+	if s.changed(last == nil || last.ClearStencil != state.ClearStencil) {
+		gl.ClearStencil(state.ClearStencil)
+	}
+	s.clear = &state
+
+	mask := gl.GLbitfield(gl.COLOR_BUFFER_BIT)
+	if state.DepthWrite {
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	if state.Stencil && state.StencilWriteMask != 0 {
+		mask |= gl.STENCIL_BUFFER_BIT
+	}
+	gl.Clear(mask)
+}
+
+// sets everything that differs from the state applied last, the clear values are left to Clear
+func (s *StateCache) ApplyRenderState(state RenderState) {
+	s.SetEnabled(gl.DEPTH_TEST, state.DepthTest)
+	s.SetEnabled(gl.BLEND, state.Blend)
+	s.SetEnabled(gl.CULL_FACE, state.CullFace)
+	s.SetEnabled(gl.SCISSOR_TEST, state.Scissor)
+	s.SetEnabled(gl.STENCIL_TEST, state.Stencil)
+
+	last := s.render
+	if s.changed(last == nil || last.DepthWrite != state.DepthWrite) {
+		gl.DepthMask(state.DepthWrite)
+	}
+	if s.changed(last == nil || last.DepthFunc != state.DepthFunc) {
+		gl.DepthFunc(state.DepthFunc)
+	}
+
+	if s.changed(last == nil || last.BlendEquation != state.BlendEquation || last.BlendEquationAlpha != state.BlendEquationAlpha) {
+		gl.BlendEquationSeparate(state.BlendEquation, state.BlendEquationAlpha)
+	}
+	if s.changed(last == nil || last.BlendSrc != state.BlendSrc || last.BlendDst != state.BlendDst ||
+		last.BlendSrcAlpha != state.BlendSrcAlpha || last.BlendDstAlpha != state.BlendDstAlpha) {
+		gl.BlendFuncSeparate(state.BlendSrc, state.BlendDst, state.BlendSrcAlpha, state.BlendDstAlpha)
+	}
+
+	if s.changed(last == nil || last.CullMode != state.CullMode) {
+		gl.CullFace(state.CullMode)
+	}
+	if s.changed(last == nil || last.FrontFace != state.FrontFace) {
+		gl.FrontFace(state.FrontFace)
+	}
+	if GLSL != GLSLES300 && s.changed(last == nil || last.PolygonMode != state.PolygonMode) {
+		gl.PolygonMode(gl.FRONT_AND_BACK, state.PolygonMode)
+	}
+	if state.LineWidth == 0 && last != nil {
+		state.LineWidth = last.LineWidth
+	} else if state.LineWidth > 0 && s.changed(last == nil || last.LineWidth != state.LineWidth) {
+		gl.LineWidth(state.LineWidth)
+	}
+
+	if s.changed(last == nil || last.ScissorBox != state.ScissorBox) {
+		box := state.ScissorBox
+		gl.Scissor(box[0], box[1], box[2], box[3])
+	}
+
+	if s.changed(last == nil || last.StencilFunc != state.StencilFunc || last.StencilRef != state.StencilRef ||
+		last.StencilReadMask != state.StencilReadMask) {
+		gl.StencilFunc(state.StencilFunc, state.StencilRef, state.StencilReadMask)
+	}
+	if s.changed(last == nil || last.StencilFail != state.StencilFail || last.StencilDepthFail != state.StencilDepthFail ||
+		last.StencilPass != state.StencilPass) {
+		gl.StencilOp(state.StencilFail, state.StencilDepthFail, state.StencilPass)
+	}
+	if s.changed(last == nil || last.StencilWriteMask != state.StencilWriteMask) {
+		gl.StencilMask(state.StencilWriteMask)
+	}
+
+	s.render = &state
+}
+
+// counts a call that has to be made or a skipped one, returns whether to make it
+func (s *StateCache) changed(changed bool) bool {
+	if !changed {
+		s.skip()
+		return false
+	}
+	s.Calls++
+	return true
+}
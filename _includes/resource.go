@@ -0,0 +1,224 @@
+package _includes
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+)
+
+type resource struct {
+	id      int
+	key     resourceKey
+	callers []uintptr
+}
+
+// names of container objects (vertex arrays and framebuffers) are only unique within their context,
+// context is 0 for everything else
+type resourceKey struct {
+	object  interface{}
+	context int
+}
+
+func keyOf(object interface{}, context int) resourceKey {
+	switch object.(type) {
+	case gl.VertexArray, gl.Framebuffer:
+		return resourceKey{object, context}
+	}
+	return resourceKey{object, 0}
+}
+
+// keeps track of every GL object created through _includes, so that everything still alive
+// can be freed (and reported) when the App is destroyed
+type ResourceRegistry struct {
+	// log the leak report in App.Destroy before freeing the leftovers, on by default
+	ReportLeaks bool
+	resources   map[resourceKey]*resource
+	next        int
+	// objects waiting for a debug label
+	unlabeled []resourceKey
+}
+
+var Resources = NewResourceRegistry()
+
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{
+		ReportLeaks: true,
+		resources:   make(map[resourceKey]*resource),
+	}
+}
+
+// registers a GL object created in the current context, the creation stack is recorded for the leak report
+func (r *ResourceRegistry) Track(object interface{}) {
+	callers := make([]uintptr, 32)
+	n := runtime.Callers(3, callers)
+
+	key := keyOf(object, currentContext)
+	r.next++
+	r.resources[key] = &resource{r.next, key, callers[:n]}
+	r.unlabeled = append(r.unlabeled, key)
+}
+
+// labels new objects with the function that created them, GL only accepts labels for objects
+// that have been bound once, so App.Start calls this before every frame
+func (r *ResourceRegistry) LabelObjects() {
+	unlabeled := r.unlabeled[:0]
+	for _, key := range r.unlabeled {
+		res, ok := r.resources[key]
+		if !ok || len(res.callers) == 0 {
+			continue
+		}
+		if key.context != 0 && key.context != currentContext {
+			// wait for a frame of the owning context
+			unlabeled = append(unlabeled, key)
+			continue
+		}
+		if DebugOutput.Labels {
+			name := runtime.FuncForPC(res.callers[0] - 1).Name()
+			Label(key.object, name[strings.LastIndex(name, "/")+1:])
+		}
+	}
+	r.unlabeled = unlabeled
+}
+
+// deletes a GL object and forgets about it, releasing the zero object or an unknown object does nothing.
+// Vertex arrays and framebuffers are looked up in the current context.
+func (r *ResourceRegistry) Release(object interface{}) {
+	r.ReleaseIn(currentContext, object)
+}
+
+// like Release, for vertex arrays and framebuffers created in another context
+func (r *ResourceRegistry) ReleaseIn(context int, object interface{}) {
+	key := keyOf(object, context)
+	if _, ok := r.resources[key]; !ok {
+		return
+	}
+	delete(r.resources, key)
+
+	// only the owning context knows container names
+	withContext(key.context, func() {
+		switch o := object.(type) {
+		case gl.Program:
+			o.Delete()
+		case gl.VertexArray:
+			o.Delete()
+		case gl.Buffer:
+			o.Delete()
+		case gl.Texture:
+			o.Delete()
+		case gl.Framebuffer:
+			o.Delete()
+		case gl.Renderbuffer:
+			o.Delete()
+		}
+	})
+
+	// shared names may still be bound in any context, containers only in their own
+	if key.context != 0 {
+		if app, ok := contexts[key.context]; ok {
+			app.state.forget(object)
+		}
+		return
+	}
+	for _, app := range contexts {
+		app.state.forget(object)
+	}
+}
+
+// deletes everything still alive, newest objects first
+func (r *ResourceRegistry) ReleaseAll() {
+	for _, res := range r.alive() {
+		r.ReleaseIn(res.key.context, res.key.object)
+	}
+	glh.OpenGLSentinel()
+}
+
+// forgets vertex arrays and framebuffers of context without deleting them, they die with it
+func (r *ResourceRegistry) dropContainers(context int) {
+	for key := range r.resources {
+		if key.context == context {
+			delete(r.resources, key)
+		}
+	}
+}
+
+func (r *ResourceRegistry) Count() int {
+	return len(r.resources)
+}
+
+func (r *ResourceRegistry) alive() []*resource {
+	resources := make([]*resource, 0, len(r.resources))
+	for _, res := range r.resources {
+		resources = append(resources, res)
+	}
+	sort.Sort(byNewest(resources))
+	return resources
+}
+
+// lists every object still alive together with the stack it was created from
+func (r *ResourceRegistry) LeakReport() string {
+	if len(r.resources) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d GL objects still alive:\n", len(r.resources))
+	resources := r.alive()
+	for i := len(resources) - 1; i >= 0; i-- {
+		res := resources[i]
+		fmt.Fprintf(&buf, "%T %v created at:\n", res.key.object, res.key.object)
+
+		frames := runtime.CallersFrames(res.callers)
+		for {
+			frame, more := frames.Next()
+			fmt.Fprintf(&buf, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+			if !more {
+				break
+			}
+		}
+	}
+	return buf.String()
+}
+
+type byNewest []*resource
+
+func (r byNewest) Len() int           { return len(r) }
+func (r byNewest) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byNewest) Less(i, j int) bool { return r[i].id > r[j].id }
+
+func genBuffer() gl.Buffer {
+	buffer := gl.GenBuffer()
+	Resources.Track(buffer)
+	return buffer
+}
+
+func genVertexArray() gl.VertexArray {
+	vertexArray := gl.GenVertexArray()
+	Resources.Track(vertexArray)
+	return vertexArray
+}
+
+func genTexture() gl.Texture {
+	texture := gl.GenTexture()
+	Resources.Track(texture)
+	return texture
+}
+
+func genFramebuffer() gl.Framebuffer {
+	framebuffer := gl.GenFramebuffer()
+	Resources.Track(framebuffer)
+	return framebuffer
+}
+
+func newProgram(vertexShaderSource, fragmentShaderSource string) gl.Program {
+	// create shader program for the GLSL version of the context
+	vertexShader := glh.Shader{gl.VERTEX_SHADER, AdaptShader(vertexShaderSource, gl.VERTEX_SHADER, GLSL)}
+	fragmentShader := glh.Shader{gl.FRAGMENT_SHADER, AdaptShader(fragmentShaderSource, gl.FRAGMENT_SHADER, GLSL)}
+	program := glh.NewProgram(vertexShader, fragmentShader)
+	Resources.Track(program)
+	return program
+}
@@ -0,0 +1,92 @@
+package _includes
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+	"github.com/go-gl/glh"
+)
+
+// key OnKeyDown saves a screenshot on
+var ScreenshotKey = glfw.KeyF12
+
+// reads the current framebuffer at its full (HiDPI) size, call it after drawing and before
+// the buffers get swapped. Alpha is set to opaque, blending leaves it meaningless.
+func (a *App) Screenshot() *image.NRGBA {
+	w, h := a.Window.GetFramebufferSize()
+
+	pixels := make([]uint8, w*h*4)
+	State.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, pixels)
+	glh.OpenGLSentinel()
+
+	return flipPixels(pixels, w, h)
+}
+
+// GL rows start at the bottom, image rows at the top
+func flipPixels(pixels []uint8, w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	stride := w * 4
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+stride]
+		copy(row, pixels[(h-1-y)*stride:(h-y)*stride])
+		for x := 3; x < stride; x += 4 {
+			row[x] = 255
+		}
+	}
+	return img
+}
+
+// reads the framebuffer right away and encodes it to filename on a background goroutine,
+// the channel receives the result once the file is written
+func (a *App) SaveScreenshot(filename string) <-chan error {
+	img := a.Screenshot()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- writePNG(filename, img)
+	}()
+	return done
+}
+
+// saves a timestamped screenshot after the next frame is drawn
+func (a *App) RequestScreenshot() {
+	a.screenshot = true
+}
+
+func (a *App) saveRequestedScreenshot() {
+	if !a.screenshot {
+		return
+	}
+	a.screenshot = false
+
+	filename := fmt.Sprintf("screenshot-%s.png", time.Now().Format("20060102-150405.000"))
+	done := a.SaveScreenshot(filename)
+	go func() {
+		if err := <-done; err != nil {
+			log.Printf("can't save screenshot: %v\n", err)
+			return
+		}
+		log.Printf("saved %v\n", filename)
+	}()
+}
+
+func writePNG(filename string, img image.Image) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
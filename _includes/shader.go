@@ -15,11 +15,28 @@ type Shader struct {
 	VertexBuffer  gl.Buffer
 	ElementBuffer gl.Buffer
 	Texture       gl.Texture
-	Ortho         gl.UniformLocation
-	Model         gl.UniformLocation
-	View          gl.UniformLocation
-	Projection    gl.UniformLocation
-	Normal        gl.UniformLocation
+	Stream        *StreamBuffer
+	// primitive mode and number of indices to draw the element buffer with,
+	// set Mode before SetElementArrayBuffer, quads are uploaded as triangles
+	Mode       gl.GLenum
+	Count      int
+	Ortho      gl.UniformLocation
+	Model      gl.UniformLocation
+	View       gl.UniformLocation
+	Projection gl.UniformLocation
+	Normal     gl.UniformLocation
+	// mode of the indices passed in, Mode turns into gl.TRIANGLES once quads are uploaded
+	sourceMode gl.GLenum
+	// vertex arrays aren't shared between contexts, the attribute setup is replayed for every context
+	attributes   []shaderAttribute
+	vertexArrays map[int]gl.VertexArray
+}
+
+type shaderAttribute struct {
+	name   string
+	length uint
+	size   int
+	offset interface{}
 }
 
 func init() {
@@ -64,8 +81,15 @@ func NewColoredShader(vertices *ColorVertices, vertexShaderSource, fragmentShade
 	return shader
 }
 
+// indices are uploaded as they are and shader.Mode is gl.TRIANGLES, quads and strips need NewElementShaderWithMode
 func NewElementShader(vertices *ColorVertices, indices []int32, vertexShaderSource, fragmentShaderSource string) *Shader {
+	return NewElementShaderWithMode(vertices, indices, gl.TRIANGLES, vertexShaderSource, fragmentShaderSource)
+}
+
+// quads are uploaded as triangles, draw with shader.Mode and shader.Count
+func NewElementShaderWithMode(vertices *ColorVertices, indices []int32, mode gl.GLenum, vertexShaderSource, fragmentShaderSource string) *Shader {
 	shader := NewShader(vertexShaderSource, fragmentShaderSource)
+	shader.Mode = mode
 
 	shader.SetVertexArray()
 	shader.SetVertexArrayBuffer(*vertices, gl.STATIC_DRAW)
@@ -80,11 +104,18 @@ func NewElementShader(vertices *ColorVertices, indices []int32, vertexShaderSour
 	return shader
 }
 
+// indices are uploaded as they are and shader.Mode is gl.TRIANGLES, quads and strips need NewDynamicShaderWithMode
 func NewDynamicShader(vertices *ColorVertices, indices []int32, vertexShaderSource, fragmentShaderSource string) *Shader {
+	return NewDynamicShaderWithMode(vertices, indices, gl.TRIANGLES, vertexShaderSource, fragmentShaderSource)
+}
+
+// quads are uploaded as triangles, draw with shader.Mode and shader.Count
+func NewDynamicShaderWithMode(vertices *ColorVertices, indices []int32, mode gl.GLenum, vertexShaderSource, fragmentShaderSource string) *Shader {
 	shader := NewShader(vertexShaderSource, fragmentShaderSource)
+	shader.Mode = mode
 
 	shader.SetVertexArray()
-	shader.SetVertexArrayBuffer(*vertices, gl.DYNAMIC_DRAW)
+	shader.SetStreamBuffer(*vertices)
 	shader.SetElementArrayBuffer(indices, gl.STATIC_DRAW)
 
 	shader.EnableColorVertexAttributes()
@@ -130,8 +161,15 @@ func NewImageTexturedShader(vertices *TextureVertices, texture *image.NRGBA, ver
 	return shader
 }
 
+// indices are uploaded as they are and shader.Mode is gl.TRIANGLES, quads and strips need NewNormalShaderWithMode
 func NewNormalShader(vertices *NormalVertices, indices []int32, vertexShaderSource, fragmentShaderSource string) *Shader {
+	return NewNormalShaderWithMode(vertices, indices, gl.TRIANGLES, vertexShaderSource, fragmentShaderSource)
+}
+
+// quads are uploaded as triangles, draw with shader.Mode and shader.Count
+func NewNormalShaderWithMode(vertices *NormalVertices, indices []int32, mode gl.GLenum, vertexShaderSource, fragmentShaderSource string) *Shader {
 	shader := NewShader(vertexShaderSource, fragmentShaderSource)
+	shader.Mode = mode
 
 	shader.SetVertexArray()
 	shader.SetVertexArrayBuffer(*vertices, gl.STATIC_DRAW)
@@ -146,8 +184,15 @@ func NewNormalShader(vertices *NormalVertices, indices []int32, vertexShaderSour
 	return shader
 }
 
+// indices are uploaded as they are and shader.Mode is gl.TRIANGLES, quads and strips need NewNormalTexturedShaderWithMode
 func NewNormalTexturedShader(vertices *NormalTextureVertices, indices []int32, texture *image.NRGBA, vertexShaderSource, fragmentShaderSource string) *Shader {
+	return NewNormalTexturedShaderWithMode(vertices, indices, gl.TRIANGLES, texture, vertexShaderSource, fragmentShaderSource)
+}
+
+// quads are uploaded as triangles, draw with shader.Mode and shader.Count
+func NewNormalTexturedShaderWithMode(vertices *NormalTextureVertices, indices []int32, mode gl.GLenum, texture *image.NRGBA, vertexShaderSource, fragmentShaderSource string) *Shader {
 	shader := NewShader(vertexShaderSource, fragmentShaderSource)
+	shader.Mode = mode
 
 	shader.SetVertexArray()
 	shader.SetVertexArrayBuffer(*vertices, gl.STATIC_DRAW)
@@ -165,11 +210,8 @@ func NewNormalTexturedShader(vertices *NormalTextureVertices, indices []int32, t
 }
 
 func NewShader(vertexShaderSource, fragmentShaderSource string) *Shader {
-	// create shader program
-	vertexShader := glh.Shader{gl.VERTEX_SHADER, vertexShaderSource}
-	fragmentShader := glh.Shader{gl.FRAGMENT_SHADER, fragmentShaderSource}
-	shader := glh.NewProgram(vertexShader, fragmentShader)
-	shader.Use()
+	shader := newProgram(vertexShaderSource, fragmentShaderSource)
+	State.UseProgram(shader)
 	glh.OpenGLSentinel()
 
 	return &Shader{
@@ -178,94 +220,120 @@ func NewShader(vertexShaderSource, fragmentShaderSource string) *Shader {
 }
 
 func (shader *Shader) Use() {
-	shader.Program.Use()
-	shader.Texture.Bind(gl.TEXTURE_2D)
-	shader.VertexArray.Bind()
-	shader.VertexBuffer.Bind(gl.ARRAY_BUFFER)
-	shader.ElementBuffer.Bind(gl.ELEMENT_ARRAY_BUFFER)
+	State.UseProgram(shader.Program)
+	State.BindTextureUnit(0, gl.TEXTURE_2D, shader.Texture)
+	shader.BindVertexArray()
+	State.BindBuffer(gl.ARRAY_BUFFER, shader.VertexBuffer)
+	State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, shader.ElementBuffer)
 }
 
 func (shader *Shader) Unuse() {
-	shader.VertexArray.Unbind()
-	shader.VertexBuffer.Unbind(gl.ARRAY_BUFFER)
-	shader.ElementBuffer.Unbind(gl.ELEMENT_ARRAY_BUFFER)
-	shader.Texture.Unbind(gl.TEXTURE_2D)
-	shader.Program.Unuse()
+	if State.LazyUnbind {
+		return
+	}
+
+	State.BindVertexArray(0)
+	State.BindBuffer(gl.ARRAY_BUFFER, 0)
+	State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	State.BindTextureUnit(0, gl.TEXTURE_2D, 0)
+	State.UseProgram(0)
+}
+
+// frees all GL objects of the shader, including its texture
+func (shader *Shader) Delete() {
+	for context, vertexArray := range shader.vertexArrays {
+		Resources.ReleaseIn(context, vertexArray)
+	}
+	Resources.Release(shader.VertexBuffer)
+	Resources.Release(shader.ElementBuffer)
+	Resources.Release(shader.Texture)
+	Resources.Release(shader.Program)
+	*shader = Shader{}
 }
 
 func (shader *Shader) SetVertexArray() {
 	// create vertex array object
-	vertexArray := gl.GenVertexArray()
-	vertexArray.Bind()
+	vertexArray := genVertexArray()
+	State.BindVertexArray(vertexArray)
 	glh.OpenGLSentinel()
 
 	shader.VertexArray = vertexArray
+	if shader.vertexArrays == nil {
+		shader.vertexArrays = make(map[int]gl.VertexArray)
+	}
+	shader.vertexArrays[currentContext] = vertexArray
 }
 
-func (shader *Shader) SetVertexArrayBuffer(data interface{}, mode gl.GLenum) {
-	var size int
-	switch d := data.(type) {
-	case Vertices:
-		size = len(d) * int(unsafe.Sizeof(Vertex{}))
-	case ColorVertices:
-		size = len(d) * int(unsafe.Sizeof(ColorVertex{}))
-	case TextureVertices:
-		size = len(d) * int(unsafe.Sizeof(TextureVertex{}))
-	case NormalVertices:
-		size = len(d) * int(unsafe.Sizeof(NormalVertex{}))
-	case NormalTextureVertices:
-		size = len(d) * int(unsafe.Sizeof(NormalTextureVertex{}))
-	default:
-		panic("unknown vertex type provided!")
+// binds the vertex array of the current context, building it first when the shader is used in a new one
+func (shader *Shader) BindVertexArray() {
+	if shader.VertexArray == 0 {
+		State.BindVertexArray(0)
+		return
+	}
+	if vertexArray, ok := shader.vertexArrays[currentContext]; ok {
+		shader.VertexArray = vertexArray
+		State.BindVertexArray(vertexArray)
+		return
+	}
+
+	shader.SetVertexArray()
+	State.BindBuffer(gl.ARRAY_BUFFER, shader.VertexBuffer)
+	if shader.ElementBuffer != 0 {
+		State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, shader.ElementBuffer)
+	}
+
+	attributes := shader.attributes
+	shader.attributes = nil
+	for _, a := range attributes {
+		shader.EnableVertexAttribute(a.name, a.length, a.size, a.offset)
 	}
+}
+
+func (shader *Shader) SetVertexArrayBuffer(data interface{}, mode gl.GLenum) {
+	layout, count := LayoutOf(data)
+	size := count * layout.Stride
 
 	// create vertex buffer object
-	vertexBuffer := gl.GenBuffer()
-	vertexBuffer.Bind(gl.ARRAY_BUFFER)
+	vertexBuffer := genBuffer()
+	State.BindBuffer(gl.ARRAY_BUFFER, vertexBuffer)
 	gl.BufferData(gl.ARRAY_BUFFER, size, data, mode)
 	glh.OpenGLSentinel()
 
 	shader.VertexBuffer = vertexBuffer
 }
 
-func (shader *Shader) SetElementArrayBuffer(indices []int32, mode gl.GLenum) {
+// like SetVertexArrayBuffer, but the vertices are meant to be replaced every frame through shader.Stream
+func (shader *Shader) SetStreamBuffer(data interface{}) {
+	shader.Stream = NewStreamBuffer(data)
+	shader.VertexBuffer = shader.Stream.Buffer
+}
+
+// uploads indices for drawing with shader.Mode, quads are turned into triangles,
+// so draw with shader.Mode and shader.Count afterwards
+func (shader *Shader) SetElementArrayBuffer(indices []int32, usage gl.GLenum) {
+	// after an upload of quads Mode is gl.TRIANGLES, later uploads are quads again
+	if shader.sourceMode == 0 || shader.Mode != gl.TRIANGLES {
+		shader.sourceMode = shader.Mode
+	}
+	triangulated, mode := Triangulate(indexSlice(indices), shader.sourceMode)
+
 	// create element array buffer object
-	elementBuffer := gl.GenBuffer()
-	elementBuffer.Bind(gl.ELEMENT_ARRAY_BUFFER)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*int(glh.Sizeof(gl.UNSIGNED_INT)), indices, mode)
+	elementBuffer := genBuffer()
+	State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, elementBuffer)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(triangulated)*int(glh.Sizeof(gl.UNSIGNED_INT)), triangulated, usage)
 	glh.OpenGLSentinel()
 
 	shader.ElementBuffer = elementBuffer
+	shader.Mode = mode
+	shader.Count = len(triangulated)
 }
 
 func (shader *Shader) SetTexture(width, height int, data *[]mgl.Vec4) {
-	// create texture
-	texture := gl.GenTexture()
-	texture.Bind(gl.TEXTURE_2D)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.FLOAT, nil)
-	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, width, height, gl.RGBA, gl.FLOAT, &((*data)[0]))
-
-	gl.GenerateMipmap(gl.TEXTURE_2D)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-	glh.OpenGLSentinel()
-
-	shader.Texture = texture
+	shader.Texture = NewTexture(width, height, data)
 }
 
 func (shader *Shader) SetImageTexture(tex *image.NRGBA) {
-	// create texture
-	texture := gl.GenTexture()
-	texture.Bind(gl.TEXTURE_2D)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, tex.Bounds().Dx(), tex.Bounds().Dy(), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
-	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, tex.Bounds().Dx(), tex.Bounds().Dy(), gl.RGBA, gl.UNSIGNED_BYTE, tex.Pix)
-
-	gl.GenerateMipmap(gl.TEXTURE_2D)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	glh.OpenGLSentinel()
-
-	shader.Texture = texture
+	shader.Texture = NewImageTexture(tex)
 }
 
 func (shader *Shader) EnableVertexAttribute(name string, length uint, size int, offset interface{}) {
@@ -273,33 +341,42 @@ func (shader *Shader) EnableVertexAttribute(name string, length uint, size int,
 	attrib.EnableArray()
 	attrib.AttribPointer(length, gl.FLOAT, false, size, offset)
 	glh.OpenGLSentinel()
+
+	shader.attributes = append(shader.attributes, shaderAttribute{name, length, size, offset})
+}
+
+func (shader *Shader) EnableVertexLayout(layout VertexLayout) {
+	for _, attribute := range layout.Attributes {
+		var offset interface{}
+		if attribute.Offset != 0 {
+			offset = attribute.Offset
+		}
+		shader.EnableVertexAttribute(attribute.Name, attribute.Length, layout.Stride, offset)
+	}
 }
 
 func (shader *Shader) EnableVertexAttributes() {
-	shader.EnableVertexAttribute("position", 4, int(unsafe.Sizeof(Vertex{})), nil)
+	shader.EnableVertexLayout(VertexLayoutPosition)
 }
 
 func (shader *Shader) EnableColorVertexAttributes() {
-	shader.EnableVertexAttribute("position", 4, int(unsafe.Sizeof(ColorVertex{})), nil)
-	shader.EnableVertexAttribute("color", 4, int(unsafe.Sizeof(ColorVertex{})), unsafe.Sizeof(mgl.Vec4{}))
+	shader.EnableVertexLayout(VertexLayoutColor)
 }
 
 func (shader *Shader) EnableTextureVertexAttributes() {
-	shader.EnableVertexAttribute("position", 4, int(unsafe.Sizeof(TextureVertex{})), nil)
-	shader.EnableVertexAttribute("textureCoordinate", 2, int(unsafe.Sizeof(TextureVertex{})), unsafe.Sizeof(mgl.Vec4{}))
+	shader.EnableVertexLayout(VertexLayoutTexture)
+}
+
+func (shader *Shader) EnableColorTextureVertexAttributes() {
+	shader.EnableVertexLayout(VertexLayoutColorTexture)
 }
 
 func (shader *Shader) EnableNormalVertexAttributes() {
-	shader.EnableVertexAttribute("position", 4, int(unsafe.Sizeof(NormalVertex{})), nil)
-	shader.EnableVertexAttribute("color", 4, int(unsafe.Sizeof(NormalVertex{})), unsafe.Sizeof(mgl.Vec4{}))
-	shader.EnableVertexAttribute("norm", 3, int(unsafe.Sizeof(NormalVertex{})), uintptr(int(unsafe.Sizeof(mgl.Vec4{}))*2))
+	shader.EnableVertexLayout(VertexLayoutNormal)
 }
 
 func (shader *Shader) EnableNormalTextureVertexAttributes() {
-	shader.EnableVertexAttribute("position", 4, int(unsafe.Sizeof(NormalTextureVertex{})), nil)
-	shader.EnableVertexAttribute("color", 4, int(unsafe.Sizeof(NormalTextureVertex{})), unsafe.Sizeof(mgl.Vec4{}))
-	shader.EnableVertexAttribute("norm", 3, int(unsafe.Sizeof(NormalTextureVertex{})), uintptr(int(unsafe.Sizeof(mgl.Vec4{}))*2))
-	shader.EnableVertexAttribute("textureCoordinate", 2, int(unsafe.Sizeof(NormalTextureVertex{})), uintptr(int(unsafe.Sizeof(mgl.Vec4{}))*2+int(unsafe.Sizeof(mgl.Vec3{}))))
+	shader.EnableVertexLayout(VertexLayoutNormalTexture)
 }
 
 func (shader *Shader) SetUniformLocations() {
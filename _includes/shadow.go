@@ -0,0 +1,182 @@
+package _includes
+
+import (
+	"math"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// texture unit the shadow map is bound to, far away from the material textures
+const ShadowTextureUnit = 7
+
+const shadowVertexShaderSource = `
+	#version 130
+		in vec4 position;
+
+		uniform mat4 lightSpace;
+		uniform mat4 model;
+
+		void main()	{
+			gl_Position = lightSpace * model * position;
+		}
+`
+
+const shadowFragmentShaderSource = `
+	#version 130
+		void main() {
+		}
+`
+
+type ShadowMap struct {
+	Framebuffer gl.Framebuffer
+	Texture     gl.Texture
+	Material    *Material
+	Light       *Light
+	Size        int
+	Bias        float32
+	PCF         int
+	LightSpace  mgl.Mat4
+	// framebuffers aren't shared between contexts, one per context drawn in
+	framebuffers map[int]gl.Framebuffer
+}
+
+func NewShadowMap(light *Light, size int) *ShadowMap {
+	if light.Type == PointLight {
+		panic("shadow maps only support directional and spot lights!")
+	}
+
+	s := &ShadowMap{
+		Light:        light,
+		Size:         size,
+		Bias:         0.005,
+		PCF:          1,
+		LightSpace:   mgl.Ident4(),
+		framebuffers: make(map[int]gl.Framebuffer),
+	}
+
+	// create depth texture, everything outside of it is treated as lit
+	s.Texture = genTexture()
+	State.BindTexture(gl.TEXTURE_2D, s.Texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, size, size, 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, []float32{1, 1, 1, 1})
+
+	s.setFramebuffer()
+
+	s.Material = NewMaterial(shadowVertexShaderSource, shadowFragmentShaderSource)
+	s.Material.State.Blend = false
+
+	return s
+}
+
+// creates a framebuffer with only a depth attachment for the current context
+func (s *ShadowMap) setFramebuffer() {
+	s.Framebuffer = genFramebuffer()
+	s.Framebuffer.Bind()
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, s.Texture, 0)
+	gl.DrawBuffer(gl.NONE)
+	gl.ReadBuffer(gl.NONE)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("shadow map framebuffer is incomplete!")
+	}
+	s.Framebuffer.Unbind()
+	glh.OpenGLSentinel()
+
+	s.framebuffers[currentContext] = s.Framebuffer
+}
+
+func (s *ShadowMap) Delete() {
+	for context, framebuffer := range s.framebuffers {
+		Resources.ReleaseIn(context, framebuffer)
+		delete(s.framebuffers, context)
+	}
+	Resources.Release(s.Texture)
+	s.Material.Delete()
+	s.Framebuffer = 0
+	s.Texture = 0
+}
+
+// fits the light space projection tightly around the scene bounds
+func (s *ShadowMap) Fit(min, max mgl.Vec3) {
+	center := min.Add(max).Mul(0.5)
+	radius := max.Sub(min).Len() / 2
+
+	direction := s.Light.Direction.Normalize()
+	up := mgl.Vec3{0, 1, 0}
+	if math.Abs(float64(direction.Y())) > 0.99 {
+		up = mgl.Vec3{0, 0, 1}
+	}
+
+	var view mgl.Mat4
+	if s.Light.Type == DirectionalLight {
+		eye := center.Sub(direction.Mul(radius * 2))
+		view = mgl.LookAtV(eye, center, up)
+	} else {
+		view = mgl.LookAtV(s.Light.Position, s.Light.Position.Add(direction), up)
+	}
+
+	// bounds of the scene in light view space, the light looks down -z
+	lo := mgl.Vec3{float32(math.Inf(1)), float32(math.Inf(1)), float32(math.Inf(1))}
+	hi := mgl.Vec3{float32(math.Inf(-1)), float32(math.Inf(-1)), float32(math.Inf(-1))}
+	for i := 0; i < 8; i++ {
+		corner := min
+		if i&1 != 0 {
+			corner[0] = max[0]
+		}
+		if i&2 != 0 {
+			corner[1] = max[1]
+		}
+		if i&4 != 0 {
+			corner[2] = max[2]
+		}
+		p := mgl.TransformCoordinate(corner, view)
+		for j := 0; j < 3; j++ {
+			lo[j] = float32(math.Min(float64(lo[j]), float64(p[j])))
+			hi[j] = float32(math.Max(float64(hi[j]), float64(p[j])))
+		}
+	}
+
+	var projection mgl.Mat4
+	if s.Light.Type == DirectionalLight {
+		projection = mgl.Ortho(lo[0], hi[0], lo[1], hi[1], -hi[2], -lo[2])
+	} else {
+		near := float32(math.Max(float64(-hi[2]), 0.05))
+		far := float32(math.Max(float64(-lo[2]), float64(near)+0.1))
+		projection = mgl.Perspective(s.Light.OuterCone*2, 1, near, far)
+	}
+
+	s.LightSpace = projection.Mul4(view)
+}
+
+// starts the depth pass, draw all shadow casters with DrawMesh and finish with End
+func (s *ShadowMap) Begin() {
+	if framebuffer, ok := s.framebuffers[currentContext]; ok {
+		s.Framebuffer = framebuffer
+	} else {
+		s.setFramebuffer()
+	}
+	s.Framebuffer.Bind()
+	gl.Viewport(0, 0, s.Size, s.Size)
+
+	s.Material.Set("lightSpace", s.LightSpace)
+	s.Material.Use()
+	// depth writes have to be on to clear
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+}
+
+func (s *ShadowMap) DrawMesh(mesh *Mesh, model mgl.Mat4) error {
+	s.Material.SetUniform("model", model)
+	return mesh.Draw(s.Material)
+}
+
+func (s *ShadowMap) End(app *App) {
+	s.Material.Unuse()
+	s.Framebuffer.Unbind()
+	gl.Viewport(0, 0, app.Width, app.Height)
+	glh.OpenGLSentinel()
+}
@@ -0,0 +1,227 @@
+package _includes
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const spriteVertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec2 textureCoordinate;
+		in vec4 color;
+
+		varying vec2 texCoord;
+		varying vec4 tint;
+
+		uniform mat4 ortho;
+
+		void main()	{
+			texCoord = textureCoordinate;
+			tint = color;
+			gl_Position = ortho * position;
+		}
+`
+
+const spriteFragmentShaderSource = `
+	#version 130
+		uniform sampler2D tex;
+
+		varying vec2 texCoord;
+		varying vec4 tint;
+
+		void main() {
+			gl_FragColor = tint * texture2D(tex, texCoord);
+		}
+`
+
+type Sprite struct {
+	Texture  gl.Texture
+	Position mgl.Vec2
+	Size     mgl.Vec2
+	Rotation float32  // radians, around Origin
+	Origin   mgl.Vec2 // pivot relative to the size, {0.5, 0.5} is the center
+	UV       mgl.Vec4 // u0, v0, u1, v1
+	Tint     mgl.Vec4
+	Depth    float32 // 0 is in front, 1 is at the back
+}
+
+func NewSprite(texture gl.Texture, position, size mgl.Vec2) Sprite {
+	return Sprite{
+		Texture:  texture,
+		Position: position,
+		Size:     size,
+		Origin:   mgl.Vec2{0.5, 0.5},
+		UV:       mgl.Vec4{0, 0, 1, 1},
+		Tint:     mgl.Vec4{1, 1, 1, 1},
+	}
+}
+
+type SpriteSort int
+
+const (
+	// fewest draw calls, for opaque sprites where the depth test sorts out overlaps
+	SortByTexture SpriteSort = iota
+	// back to front, needed for blended sprites, sprites on the same depth are grouped by texture
+	SortByDepth
+)
+
+// collects sprites between Begin and End and draws them with as few draw calls as possible,
+// one for every run of sprites sharing a texture after sorting
+type SpriteBatch struct {
+	Shader    *Shader
+	Sort      SpriteSort
+	DrawCalls int
+	sprites   []Sprite
+	vertices  ColorTextureVertices
+	capacity  int
+	ortho     mgl.Mat4
+}
+
+func NewSpriteBatch(capacity int) *SpriteBatch {
+	b := &SpriteBatch{}
+
+	shader := NewShader(spriteVertexShaderSource, spriteFragmentShaderSource)
+	shader.SetVertexArray()
+	shader.SetVertexArrayBuffer(make(ColorTextureVertices, capacity*4), gl.DYNAMIC_DRAW)
+	shader.Mode = gl.TRIANGLES
+	shader.SetElementArrayBuffer(spriteIndices(capacity), gl.STATIC_DRAW)
+
+	shader.EnableColorTextureVertexAttributes()
+	shader.SetUniformLocations()
+
+	shader.Unuse()
+	glh.OpenGLSentinel()
+
+	b.Shader = shader
+	b.capacity = capacity
+	return b
+}
+
+// frees the shader, the sprite textures aren't owned by the batch
+func (b *SpriteBatch) Delete() {
+	b.Shader.Delete()
+}
+
+// two triangles per sprite
+func spriteIndices(capacity int) []int32 {
+	indices := make([]int32, 0, capacity*6)
+	for i := int32(0); i < int32(capacity); i++ {
+		indices = append(indices, i*4, i*4+1, i*4+2, i*4+2, i*4+3, i*4)
+	}
+	return indices
+}
+
+// starts a new batch in screen space pixels with the origin in the top-left corner
+func (b *SpriteBatch) Begin(app *App) {
+	b.BeginOrtho(mgl.Ortho(0, float32(app.Width), float32(app.Height), 0, -1.0, 1.0))
+}
+
+func (b *SpriteBatch) BeginOrtho(ortho mgl.Mat4) {
+	b.ortho = ortho
+	b.sprites = b.sprites[:0]
+}
+
+func (b *SpriteBatch) Draw(sprite Sprite) {
+	b.sprites = append(b.sprites, sprite)
+}
+
+func (b *SpriteBatch) End() {
+	b.DrawCalls = 0
+	if len(b.sprites) == 0 {
+		return
+	}
+
+	switch b.Sort {
+	case SortByTexture:
+		sort.Stable(spritesByTexture(b.sprites))
+	case SortByDepth:
+		sort.Stable(spritesByDepth(b.sprites))
+	}
+
+	b.vertices = b.vertices[:0]
+	for _, sprite := range b.sprites {
+		b.vertices = append(b.vertices, sprite.vertices()...)
+	}
+
+	b.Shader.Use()
+	b.Shader.Ortho.UniformMatrix4fv(false, b.ortho)
+
+	// upload all sprites at once, growing the buffers if needed
+	size := len(b.vertices) * int(unsafe.Sizeof(ColorTextureVertex{}))
+	if len(b.sprites) > b.capacity {
+		b.capacity = len(b.sprites)
+		indices := spriteIndices(b.capacity)
+		gl.BufferData(gl.ARRAY_BUFFER, size, b.vertices, gl.DYNAMIC_DRAW)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*int(glh.Sizeof(gl.UNSIGNED_INT)), indices, gl.STATIC_DRAW)
+	} else {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, b.vertices)
+	}
+
+	// one draw call for every run of sprites with the same texture
+	first := 0
+	for i := 1; i <= len(b.sprites); i++ {
+		if i < len(b.sprites) && b.sprites[i].Texture == b.sprites[first].Texture {
+			continue
+		}
+
+		State.BindTexture(gl.TEXTURE_2D, b.sprites[first].Texture)
+		gl.DrawElements(gl.TRIANGLES, (i-first)*6, gl.UNSIGNED_INT, uintptr(first*6*int(glh.Sizeof(gl.UNSIGNED_INT))))
+		b.DrawCalls++
+		first = i
+	}
+
+	b.Shader.Unuse()
+	glh.OpenGLSentinel()
+}
+
+func (sprite Sprite) vertices() ColorTextureVertices {
+	sin, cos := math.Sincos(float64(sprite.Rotation))
+	s, c := float32(sin), float32(cos)
+
+	corner := func(x, y, u, v float32) ColorTextureVertex {
+		// corner relative to the pivot, rotated and moved into place
+		lx := (x - sprite.Origin[0]) * sprite.Size[0]
+		ly := (y - sprite.Origin[1]) * sprite.Size[1]
+		return ColorTextureVertex{
+			Position:          mgl.Vec4{sprite.Position[0] + lx*c - ly*s, sprite.Position[1] + lx*s + ly*c, -sprite.Depth, 1},
+			TextureCoordinate: mgl.Vec2{u, v},
+			Color:             sprite.Tint,
+		}
+	}
+
+	u0, v0, u1, v1 := sprite.UV[0], sprite.UV[1], sprite.UV[2], sprite.UV[3]
+	return ColorTextureVertices{
+		corner(0, 0, u0, v0),
+		corner(1, 0, u1, v0),
+		corner(1, 1, u1, v1),
+		corner(0, 1, u0, v1),
+	}
+}
+
+type spritesByTexture []Sprite
+
+func (s spritesByTexture) Len() int      { return len(s) }
+func (s spritesByTexture) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s spritesByTexture) Less(i, j int) bool {
+	if s[i].Texture != s[j].Texture {
+		return s[i].Texture < s[j].Texture
+	}
+	return s[i].Depth > s[j].Depth
+}
+
+type spritesByDepth []Sprite
+
+func (s spritesByDepth) Len() int      { return len(s) }
+func (s spritesByDepth) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s spritesByDepth) Less(i, j int) bool {
+	if s[i].Depth != s[j].Depth {
+		return s[i].Depth > s[j].Depth
+	}
+	return s[i].Texture < s[j].Texture
+}
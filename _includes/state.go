@@ -0,0 +1,288 @@
+package _includes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl"
+)
+
+type textureKey struct {
+	unit   int
+	target gl.GLenum
+}
+
+// remembers bound objects and enabled capabilities of the current context and skips
+// GL calls that wouldn't change anything, anything not set through it yet counts as unknown.
+// Call Invalidate after changing any of this state with raw GL calls.
+type StateCache struct {
+	// verify the cache against glGet on every skipped call, panics on mismatches
+	Debug bool
+	// let Shader/Material/Mesh leave their objects bound after use, the next use rebinds what it needs.
+	// Off by default, raw GL calls in between would otherwise run against whatever was left bound
+	LazyUnbind bool
+	// GL calls issued and skipped since the last ResetCounters
+	Calls int
+	Saved int
+	// counters of the previous frame, see EndFrame
+	LastFrameCalls int
+	LastFrameSaved int
+
+	program       *gl.Program
+	vertexArray   *gl.VertexArray
+	activeTexture *int
+	buffers       map[gl.GLenum]gl.Buffer
+	textures      map[textureKey]gl.Texture
+	capabilities  map[gl.GLenum]bool
+	// last applied RenderState and clear values, see RenderState.Apply and Clear
+	render *RenderState
+	clear  *RenderState
+}
+
+var State = NewStateCache()
+
+func NewStateCache() *StateCache {
+	s := &StateCache{}
+	s.Invalidate()
+	return s
+}
+
+// forgets everything, the next call of every kind goes through to GL again
+func (s *StateCache) Invalidate() {
+	s.program = nil
+	s.vertexArray = nil
+	s.activeTexture = nil
+	s.buffers = make(map[gl.GLenum]gl.Buffer)
+	s.textures = make(map[textureKey]gl.Texture)
+	s.capabilities = make(map[gl.GLenum]bool)
+	s.render = nil
+	s.clear = nil
+}
+
+func (s *StateCache) ResetCounters() {
+	s.Calls = 0
+	s.Saved = 0
+}
+
+// keeps the counters of the finished frame and starts counting again, App.Start calls this after every frame
+func (s *StateCache) EndFrame() {
+	s.LastFrameCalls = s.Calls
+	s.LastFrameSaved = s.Saved
+	s.ResetCounters()
+}
+
+func (s *StateCache) skip() {
+	s.Saved++
+	if s.Debug {
+		s.Verify()
+	}
+}
+
+func (s *StateCache) UseProgram(program gl.Program) {
+	if s.program != nil && *s.program == program {
+		s.skip()
+		return
+	}
+	if program == 0 {
+		program.Unuse()
+	} else {
+		program.Use()
+	}
+	s.program = &program
+	s.Calls++
+}
+
+func (s *StateCache) BindVertexArray(vertexArray gl.VertexArray) {
+	if s.vertexArray != nil && *s.vertexArray == vertexArray {
+		s.skip()
+		return
+	}
+	if vertexArray == 0 {
+		vertexArray.Unbind()
+	} else {
+		vertexArray.Bind()
+	}
+	s.vertexArray = &vertexArray
+	s.Calls++
+
+	// the element array binding is part of the vertex array object
+	delete(s.buffers, gl.ELEMENT_ARRAY_BUFFER)
+}
+
+func (s *StateCache) BindBuffer(target gl.GLenum, buffer gl.Buffer) {
+	if bound, ok := s.buffers[target]; ok && bound == buffer {
+		s.skip()
+		return
+	}
+	if buffer == 0 {
+		buffer.Unbind(target)
+	} else {
+		buffer.Bind(target)
+	}
+	s.buffers[target] = buffer
+	s.Calls++
+}
+
+// binds buffer to an indexed binding point, which also binds it to the generic target
+func (s *StateCache) BindBufferBase(target gl.GLenum, index uint, buffer gl.Buffer) {
+	buffer.BindBufferBase(target, index)
+	s.buffers[target] = buffer
+	s.Calls++
+}
+
+func (s *StateCache) ActiveTexture(unit int) {
+	if s.activeTexture != nil && *s.activeTexture == unit {
+		s.skip()
+		return
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + gl.GLenum(unit))
+	s.activeTexture = &unit
+	s.Calls++
+}
+
+// binds texture to the active texture unit
+func (s *StateCache) BindTexture(target gl.GLenum, texture gl.Texture) {
+	unit := 0
+	if s.activeTexture != nil {
+		unit = *s.activeTexture
+	} else {
+		// unknown unit, make it known
+		s.ActiveTexture(0)
+	}
+
+	key := textureKey{unit, target}
+	if bound, ok := s.textures[key]; ok && bound == texture {
+		s.skip()
+		return
+	}
+	if texture == 0 {
+		texture.Unbind(target)
+	} else {
+		texture.Bind(target)
+	}
+	s.textures[key] = texture
+	s.Calls++
+}
+
+func (s *StateCache) BindTextureUnit(unit int, target gl.GLenum, texture gl.Texture) {
+	s.ActiveTexture(unit)
+	s.BindTexture(target, texture)
+}
+
+func (s *StateCache) SetEnabled(capability gl.GLenum, enabled bool) {
+	if current, ok := s.capabilities[capability]; ok && current == enabled {
+		s.skip()
+		return
+	}
+	if enabled {
+		gl.Enable(capability)
+	} else {
+		gl.Disable(capability)
+	}
+	s.capabilities[capability] = enabled
+	s.Calls++
+}
+
+// whether capability is enabled, asks GL the first time for capabilities not set through the cache yet
+func (s *StateCache) Enabled(capability gl.GLenum) bool {
+	if enabled, ok := s.capabilities[capability]; ok {
+		return enabled
+	}
+	enabled := gl.IsEnabled(capability)
+	s.capabilities[capability] = enabled
+	return enabled
+}
+
+func (s *StateCache) Enable(capability gl.GLenum) {
+	s.SetEnabled(capability, true)
+}
+
+func (s *StateCache) Disable(capability gl.GLenum) {
+	s.SetEnabled(capability, false)
+}
+
+// called after object got deleted, GL reverts every binding of a deleted object to 0,
+// a deleted program stays in use until another one is used
+func (s *StateCache) forget(object interface{}) {
+	switch o := object.(type) {
+	case gl.VertexArray:
+		if s.vertexArray != nil && *s.vertexArray == o {
+			var none gl.VertexArray
+			s.vertexArray = &none
+			delete(s.buffers, gl.ELEMENT_ARRAY_BUFFER)
+		}
+	case gl.Buffer:
+		for target, buffer := range s.buffers {
+			if buffer == o {
+				s.buffers[target] = 0
+			}
+		}
+	case gl.Texture:
+		for key, texture := range s.textures {
+			if texture == o {
+				s.textures[key] = 0
+			}
+		}
+	}
+}
+
+func getInteger(pname gl.GLenum) int {
+	data := make([]int32, 1)
+	gl.GetIntegerv(pname, data)
+	return int(data[0])
+}
+
+var bufferBindings = map[gl.GLenum]gl.GLenum{
+	gl.ARRAY_BUFFER:         gl.ARRAY_BUFFER_BINDING,
+	gl.ELEMENT_ARRAY_BUFFER: gl.ELEMENT_ARRAY_BUFFER_BINDING,
+	gl.UNIFORM_BUFFER:       gl.UNIFORM_BUFFER_BINDING,
+	gl.PIXEL_PACK_BUFFER:    gl.PIXEL_PACK_BUFFER_BINDING,
+}
+
+var textureBindings = map[gl.GLenum]gl.GLenum{
+	gl.TEXTURE_2D:       gl.TEXTURE_BINDING_2D,
+	gl.TEXTURE_CUBE_MAP: gl.TEXTURE_BINDING_CUBE_MAP,
+}
+
+// compares everything the cache knows with what GL reports, panics on the first difference
+func (s *StateCache) Verify() {
+	mismatch := func(what string, cached, actual interface{}) {
+		panic(fmt.Sprintf("state cache out of sync: %s is %v, cache says %v!", what, actual, cached))
+	}
+
+	if s.program != nil {
+		if actual := getInteger(gl.CURRENT_PROGRAM); actual != int(*s.program) {
+			mismatch("program", *s.program, actual)
+		}
+	}
+	if s.vertexArray != nil {
+		if actual := getInteger(gl.VERTEX_ARRAY_BINDING); actual != int(*s.vertexArray) {
+			mismatch("vertex array", *s.vertexArray, actual)
+		}
+	}
+	for target, buffer := range s.buffers {
+		if binding, ok := bufferBindings[target]; ok {
+			if actual := getInteger(binding); actual != int(buffer) {
+				mismatch(fmt.Sprintf("buffer binding 0x%x", int(target)), buffer, actual)
+			}
+		}
+	}
+	if s.activeTexture != nil {
+		if actual := getInteger(gl.ACTIVE_TEXTURE) - int(gl.TEXTURE0); actual != *s.activeTexture {
+			mismatch("active texture unit", *s.activeTexture, actual)
+		}
+		for key, texture := range s.textures {
+			binding, ok := textureBindings[key.target]
+			if !ok || key.unit != *s.activeTexture {
+				continue
+			}
+			if actual := getInteger(binding); actual != int(texture) {
+				mismatch(fmt.Sprintf("texture binding 0x%x on unit %d", int(key.target), key.unit), texture, actual)
+			}
+		}
+	}
+	for capability, enabled := range s.capabilities {
+		if actual := gl.IsEnabled(capability); actual != enabled {
+			mismatch(fmt.Sprintf("capability 0x%x", int(capability)), enabled, actual)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package _includes
+
+import (
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// vertex buffer for geometry that changes every frame, Write orphans the old storage before
+// uploading so the driver hands out fresh memory instead of waiting for draws still reading it
+type StreamBuffer struct {
+	Buffer   gl.Buffer
+	Layout   VertexLayout
+	Count    int
+	capacity int
+	// positions of the vertices written, only kept while a stream mesh needs them for picking and bounds
+	positions        []mgl.Vec3
+	positionsChanged func([]mgl.Vec3)
+}
+
+func NewStreamBuffer(vertices interface{}) *StreamBuffer {
+	layout, count := LayoutOf(vertices)
+
+	b := &StreamBuffer{
+		Buffer:   genBuffer(),
+		Layout:   layout,
+		capacity: count,
+	}
+	b.Write(vertices)
+
+	return b
+}
+
+// replaces all vertices, the vertex count follows the slice length and the buffer grows when needed
+func (b *StreamBuffer) Write(vertices interface{}) {
+	count := b.count(vertices)
+	if count > b.capacity {
+		b.capacity = count
+	}
+
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	gl.BufferData(gl.ARRAY_BUFFER, b.capacity*b.Layout.Stride, nil, gl.STREAM_DRAW)
+	if count > 0 {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, count*b.Layout.Stride, vertices)
+	}
+	glh.OpenGLSentinel()
+
+	b.Count = count
+	if b.positionsChanged != nil {
+		b.positions = PositionsOf(vertices)
+		b.positionsChanged(b.positions)
+	}
+}
+
+// overwrites the vertices starting at index first and keeps all others,
+// e.g. UpdateRange(10, vertices[10:20]) uploads just those ten vertices
+func (b *StreamBuffer) UpdateRange(first int, vertices interface{}) {
+	count := b.count(vertices)
+	if first < 0 || first+count > b.Count {
+		panic("vertex range out of bounds!")
+	}
+	if count == 0 {
+		return
+	}
+
+	State.BindBuffer(gl.ARRAY_BUFFER, b.Buffer)
+	gl.BufferSubData(gl.ARRAY_BUFFER, first*b.Layout.Stride, count*b.Layout.Stride, vertices)
+	glh.OpenGLSentinel()
+
+	if b.positionsChanged != nil {
+		copy(b.positions[first:], PositionsOf(vertices))
+		b.positionsChanged(b.positions)
+	}
+}
+
+func (b *StreamBuffer) count(vertices interface{}) int {
+	layout, count := LayoutOf(vertices)
+	if layout.Stride != b.Layout.Stride {
+		panic("vertex type does not match the stream buffer!")
+	}
+	return count
+}
+
+func (b *StreamBuffer) Delete() {
+	Resources.Release(b.Buffer)
+	b.Buffer = 0
+	b.Count = 0
+	b.capacity = 0
+}
@@ -0,0 +1,168 @@
+package _includes
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const textVertexShaderSource = `
+	#version 130
+		in vec4 position;
+		in vec2 textureCoordinate;
+
+		varying vec2 texCoord;
+
+		uniform mat4 ortho;
+		uniform mat4 model;
+		uniform mat4 view;
+		uniform mat4 projection;
+		uniform float billboard;
+		uniform float scale;
+		uniform vec2 anchor;
+
+		void main()	{
+			texCoord = textureCoordinate;
+			if (billboard > 0.5) {
+				// keep the quads facing the camera, text is laid out with y pointing down
+				vec4 center = view * model * vec4(0.0, 0.0, 0.0, 1.0);
+				gl_Position = projection * (center + vec4((position.x - anchor.x) * scale, (anchor.y - position.y) * scale, 0.0, 0.0));
+			} else {
+				gl_Position = ortho * model * position;
+			}
+		}
+`
+
+const textFragmentShaderSource = `
+	#version 130
+		uniform sampler2D atlas;
+		uniform vec4 color;
+
+		varying vec2 texCoord;
+
+		void main() {
+			gl_FragColor = vec4(color.rgb, color.a * texture2D(atlas, texCoord).a);
+		}
+`
+
+type Text struct {
+	Font      *Font
+	Shader    *Shader
+	Vertices  TextureVertices
+	Align     TextAlign
+	Color     mgl.Vec4
+	Width     float32
+	Height    float32
+	capacity  int
+	billboard gl.UniformLocation
+	scale     gl.UniformLocation
+	anchor    gl.UniformLocation
+	color     gl.UniformLocation
+}
+
+func NewText(font *Font, text string, align TextAlign) *Text {
+	t := &Text{
+		Font:     font,
+		Align:    align,
+		Color:    mgl.Vec4{1, 1, 1, 1},
+		capacity: 64,
+	}
+
+	shader := NewShader(textVertexShaderSource, textFragmentShaderSource)
+	shader.SetVertexArray()
+	shader.SetVertexArrayBuffer(make(TextureVertices, t.capacity*4), gl.DYNAMIC_DRAW)
+	shader.Mode = gl.TRIANGLES
+	shader.SetElementArrayBuffer(spriteIndices(t.capacity), gl.STATIC_DRAW)
+
+	shader.EnableTextureVertexAttributes()
+	shader.SetUniformLocations()
+	t.billboard = shader.Program.GetUniformLocation("billboard")
+	t.scale = shader.Program.GetUniformLocation("scale")
+	t.anchor = shader.Program.GetUniformLocation("anchor")
+	t.color = shader.Program.GetUniformLocation("color")
+
+	shader.SetImageTexture(font.Atlas)
+	// glyphs are rasterized at their final pixel size, no need for mipmaps
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+
+	shader.Unuse()
+	glh.OpenGLSentinel()
+
+	t.Shader = shader
+	t.SetText(text)
+
+	return t
+}
+
+// frees the shader and the glyph atlas texture, the Font can be used for new texts
+func (t *Text) Delete() {
+	t.Shader.Delete()
+}
+
+func (t *Text) SetText(text string) {
+	t.Vertices = t.Font.Layout(text, t.Align)
+
+	widths, height := t.Font.Measure(text)
+	t.Width = 0
+	for _, w := range widths {
+		if w > t.Width {
+			t.Width = w
+		}
+	}
+	t.Height = height
+
+	if len(t.Vertices) == 0 {
+		return
+	}
+
+	State.BindBuffer(gl.ARRAY_BUFFER, t.Shader.VertexBuffer)
+	size := len(t.Vertices) * int(unsafe.Sizeof(TextureVertex{}))
+	if len(t.Vertices) > t.capacity*4 {
+		// grow buffers to fit the new text
+		t.capacity = len(t.Vertices) / 4
+		gl.BufferData(gl.ARRAY_BUFFER, size, t.Vertices, gl.DYNAMIC_DRAW)
+
+		// the element buffer binding belongs to our vertex array object
+		t.Shader.BindVertexArray()
+		State.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, t.Shader.ElementBuffer)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, t.capacity*6*int(glh.Sizeof(gl.UNSIGNED_INT)), spriteIndices(t.capacity), gl.STATIC_DRAW)
+	} else {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, t.Vertices)
+	}
+	glh.OpenGLSentinel()
+}
+
+// draws the text in screen space, x and y are pixel coordinates of the top-left corner
+func (t *Text) Draw(app *App, x, y float32) {
+	ortho := mgl.Ortho(0, float32(app.Width), float32(app.Height), 0, -1.0, 1.0)
+
+	t.Shader.Use()
+	t.Shader.Ortho.UniformMatrix4fv(false, ortho)
+	t.Shader.Model.UniformMatrix4fv(false, mgl.Translate3D(x, y, 0))
+	t.billboard.Uniform1f(0)
+	t.draw()
+	t.Shader.Unuse()
+}
+
+// draws the text in 3D, always facing the camera and centered on position,
+// scale converts from font pixels into world units
+func (t *Text) DrawBillboard(view, projection mgl.Mat4, position mgl.Vec3, scale float32) {
+	model := mgl.Translate3D(position.X(), position.Y(), position.Z())
+
+	t.Shader.Use()
+	t.Shader.Model.UniformMatrix4fv(false, model)
+	t.Shader.View.UniformMatrix4fv(false, view)
+	t.Shader.Projection.UniformMatrix4fv(false, projection)
+	t.billboard.Uniform1f(1)
+	t.scale.Uniform1f(scale)
+	t.anchor.Uniform2f(t.Width/2, t.Height/2)
+	t.draw()
+	t.Shader.Unuse()
+}
+
+func (t *Text) draw() {
+	t.color.Uniform4f(t.Color[0], t.Color[1], t.Color[2], t.Color[3])
+	gl.DrawElements(gl.TRIANGLES, len(t.Vertices)/4*6, gl.UNSIGNED_INT, nil)
+}
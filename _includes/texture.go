@@ -0,0 +1,39 @@
+package _includes
+
+import (
+	"image"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func NewTexture(width, height int, data *[]mgl.Vec4) gl.Texture {
+	// create texture
+	texture := genTexture()
+	State.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.FLOAT, nil)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, width, height, gl.RGBA, gl.FLOAT, &((*data)[0]))
+
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	glh.OpenGLSentinel()
+
+	return texture
+}
+
+func NewImageTexture(tex *image.NRGBA) gl.Texture {
+	// create texture
+	texture := genTexture()
+	State.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, tex.Bounds().Dx(), tex.Bounds().Dy(), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, tex.Bounds().Dx(), tex.Bounds().Dy(), gl.RGBA, gl.UNSIGNED_BYTE, tex.Pix)
+
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	glh.OpenGLSentinel()
+
+	return texture
+}
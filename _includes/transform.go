@@ -0,0 +1,223 @@
+package _includes
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// position, rotation and scale of an object, points are scaled first, then rotated, then moved.
+// Composition and inverse are exact as long as scaling is uniform, non-uniform scales get
+// approximated, use the matrices for those. Start from NewTransform, the zero value has no valid rotation.
+type Transform struct {
+	Position mgl.Vec3
+	Rotation mgl.Quat
+	Scale    mgl.Vec3
+}
+
+func NewTransform() Transform {
+	return Transform{
+		Rotation: mgl.QuatIdent(),
+		Scale:    mgl.Vec3{1, 1, 1},
+	}
+}
+
+func TransformAt(position mgl.Vec3) Transform {
+	t := NewTransform()
+	t.Position = position
+	return t
+}
+
+func (t Transform) Translate(offset mgl.Vec3) Transform {
+	t.Position = t.Position.Add(offset)
+	return t
+}
+
+// rotates around axis in world space, angle in radians
+func (t Transform) Rotate(angle float32, axis mgl.Vec3) Transform {
+	t.Rotation = mgl.QuatRotate(angle, axis.Normalize()).Mul(t.Rotation).Normalize()
+	return t
+}
+
+func (t Transform) Scaled(factor float32) Transform {
+	t.Scale = t.Scale.Mul(factor)
+	return t
+}
+
+// turns the -z axis towards target, like mgl.LookAtV does for cameras
+func (t Transform) LookAt(target, up mgl.Vec3) Transform {
+	forward := target.Sub(t.Position)
+	if forward.Len() == 0 {
+		return t
+	}
+	forward = forward.Normalize()
+
+	right := forward.Cross(up)
+	if right.Len() < 1e-6 {
+		// up is parallel to the view direction, any perpendicular axis does
+		right = forward.Cross(mgl.Vec3{1, 0, 0})
+		if right.Len() < 1e-6 {
+			right = forward.Cross(mgl.Vec3{0, 0, 1})
+		}
+	}
+	right = right.Normalize()
+
+	t.Rotation = quatFromBasis(right, right.Cross(forward), forward.Mul(-1))
+	return t
+}
+
+// model matrix, translation * rotation * scale
+func (t Transform) Matrix() mgl.Mat4 {
+	return mgl.Translate3D(t.Position[0], t.Position[1], t.Position[2]).
+		Mul4(t.Rotation.Mat4()).
+		Mul4(mgl.Scale3D(t.Scale[0], t.Scale[1], t.Scale[2]))
+}
+
+// inverse transpose of the model matrix for world space normals,
+// rotation * inverse scale, so no matrix has to be inverted
+func (t Transform) NormalMatrix() mgl.Mat3 {
+	return t.Rotation.Mat4().Mul4(mgl.Scale3D(1/t.Scale[0], 1/t.Scale[1], 1/t.Scale[2])).Mat3()
+}
+
+// normal matrix for view space normals, view must only rotate and translate, which mgl.LookAtV does
+func (t Transform) ViewNormalMatrix(view mgl.Mat4) mgl.Mat3 {
+	return view.Mat3().Mul3(t.NormalMatrix())
+}
+
+// the transform of child relative to t, in the world
+func (t Transform) Mul(child Transform) Transform {
+	scaled := mgl.Vec3{t.Scale[0] * child.Position[0], t.Scale[1] * child.Position[1], t.Scale[2] * child.Position[2]}
+	return Transform{
+		Position: t.Position.Add(t.Rotation.Rotate(scaled)),
+		Rotation: t.Rotation.Mul(child.Rotation).Normalize(),
+		Scale:    mgl.Vec3{t.Scale[0] * child.Scale[0], t.Scale[1] * child.Scale[1], t.Scale[2] * child.Scale[2]},
+	}
+}
+
+func (t Transform) Inverse() Transform {
+	rotation := t.Rotation.Inverse()
+	scale := mgl.Vec3{1 / t.Scale[0], 1 / t.Scale[1], 1 / t.Scale[2]}
+	position := rotation.Rotate(t.Position.Mul(-1))
+	return Transform{
+		Position: mgl.Vec3{scale[0] * position[0], scale[1] * position[1], scale[2] * position[2]},
+		Rotation: rotation,
+		Scale:    scale,
+	}
+}
+
+// transforms a point from object into world space
+func (t Transform) Point(p mgl.Vec3) mgl.Vec3 {
+	scaled := mgl.Vec3{t.Scale[0] * p[0], t.Scale[1] * p[1], t.Scale[2] * p[2]}
+	return t.Position.Add(t.Rotation.Rotate(scaled))
+}
+
+// transforms a direction, which ignores the position
+func (t Transform) Direction(d mgl.Vec3) mgl.Vec3 {
+	return t.Rotation.Rotate(mgl.Vec3{t.Scale[0] * d[0], t.Scale[1] * d[1], t.Scale[2] * d[2]})
+}
+
+// interpolates linearly, the rotation is normalized afterwards (nlerp),
+// cheaper than Slerp but its angular speed isn't constant
+func LerpTransform(a, b Transform, f float32) Transform {
+	to := shortestPath(a.Rotation, b.Rotation)
+	rotation := mgl.Quat{
+		W: a.Rotation.W + (to.W-a.Rotation.W)*f,
+		V: a.Rotation.V.Add(to.V.Sub(a.Rotation.V).Mul(f)),
+	}
+	return Transform{
+		Position: lerpVec3(a.Position, b.Position, f),
+		Rotation: rotation.Normalize(),
+		Scale:    lerpVec3(a.Scale, b.Scale, f),
+	}
+}
+
+// interpolates position and scale linearly and the rotation along the shortest arc at constant speed
+func SlerpTransform(a, b Transform, f float32) Transform {
+	t := LerpTransform(a, b, f)
+	t.Rotation = slerp(a.Rotation, b.Rotation, f)
+	return t
+}
+
+func lerpVec3(a, b mgl.Vec3, f float32) mgl.Vec3 {
+	return a.Add(b.Sub(a).Mul(f))
+}
+
+func quatDot(a, b mgl.Quat) float32 {
+	return a.W*b.W + a.V.Dot(b.V)
+}
+
+// q and -q are the same rotation, the one closer to from takes the short way
+func shortestPath(from, to mgl.Quat) mgl.Quat {
+	if quatDot(from, to) < 0 {
+		return mgl.Quat{W: -to.W, V: to.V.Mul(-1)}
+	}
+	return to
+}
+
+func slerp(a, b mgl.Quat, f float32) mgl.Quat {
+	b = shortestPath(a, b)
+	cos := float64(quatDot(a, b))
+	if cos > 0.9995 {
+		// nearly the same, nlerp avoids dividing by a tiny sine
+		return LerpTransform(Transform{Rotation: a}, Transform{Rotation: b}, f).Rotation
+	}
+
+	angle := math.Acos(cos)
+	sin := math.Sin(angle)
+	wa := float32(math.Sin((1-float64(f))*angle) / sin)
+	wb := float32(math.Sin(float64(f)*angle) / sin)
+	return mgl.Quat{
+		W: a.W*wa + b.W*wb,
+		V: a.V.Mul(wa).Add(b.V.Mul(wb)),
+	}.Normalize()
+}
+
+// splits a matrix built from translation, rotation and scale back into them,
+// a mirroring matrix gets a negative x scale. Shear is lost.
+func DecomposeMatrix(m mgl.Mat4) Transform {
+	x := mgl.Vec3{m[0], m[1], m[2]}
+	y := mgl.Vec3{m[4], m[5], m[6]}
+	z := mgl.Vec3{m[8], m[9], m[10]}
+
+	scale := mgl.Vec3{x.Len(), y.Len(), z.Len()}
+	if x.Cross(y).Dot(z) < 0 {
+		scale[0] = -scale[0]
+	}
+	for i := range scale {
+		if scale[i] == 0 {
+			// degenerate axis, keep the rotation of the others
+			scale[i] = 1e-12
+		}
+	}
+
+	return Transform{
+		Position: mgl.Vec3{m[12], m[13], m[14]},
+		Rotation: quatFromBasis(x.Mul(1/scale[0]), y.Mul(1/scale[1]), z.Mul(1/scale[2])),
+		Scale:    scale,
+	}
+}
+
+// rotation taking the unit axes to the orthonormal x, y and z
+func quatFromBasis(x, y, z mgl.Vec3) mgl.Quat {
+	var q mgl.Quat
+	trace := x[0] + y[1] + z[2]
+	switch {
+	case trace > 0:
+		s := float32(math.Sqrt(float64(trace+1))) * 2
+		q.W = s / 4
+		q.V = mgl.Vec3{(y[2] - z[1]) / s, (z[0] - x[2]) / s, (x[1] - y[0]) / s}
+	case x[0] > y[1] && x[0] > z[2]:
+		s := float32(math.Sqrt(float64(1+x[0]-y[1]-z[2]))) * 2
+		q.W = (y[2] - z[1]) / s
+		q.V = mgl.Vec3{s / 4, (y[0] + x[1]) / s, (z[0] + x[2]) / s}
+	case y[1] > z[2]:
+		s := float32(math.Sqrt(float64(1+y[1]-x[0]-z[2]))) * 2
+		q.W = (z[0] - x[2]) / s
+		q.V = mgl.Vec3{(y[0] + x[1]) / s, s / 4, (z[1] + y[2]) / s}
+	default:
+		s := float32(math.Sqrt(float64(1+z[2]-x[0]-y[1]))) * 2
+		q.W = (x[1] - y[0]) / s
+		q.V = mgl.Vec3{(z[0] + x[2]) / s, (z[1] + y[2]) / s, s / 4}
+	}
+	return q.Normalize()
+}
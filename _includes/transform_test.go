@@ -0,0 +1,172 @@
+package _includes
+
+import (
+	"math"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// q and -q are the same rotation
+func sameRotation(a, b mgl.Quat) bool {
+	return near(float32(math.Abs(float64(quatDot(a, b)))), 1)
+}
+
+func TestDecomposeMatrix(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+	}{
+		{"identity", NewTransform()},
+		{"translated", TransformAt(mgl.Vec3{1, -2, 3})},
+		{"rotated", NewTransform().Rotate(0.7, mgl.Vec3{0, 1, 0})},
+		{"rotated half a turn", NewTransform().Rotate(math.Pi, mgl.Vec3{1, 0, 0})},
+		{"scaled", NewTransform().Scaled(2.5)},
+		{"scaled non-uniformly", Transform{Rotation: mgl.QuatIdent(), Scale: mgl.Vec3{1, 2, 3}}},
+		{"everything", Transform{
+			Position: mgl.Vec3{4, 5, -6},
+			Rotation: mgl.QuatRotate(1.2, mgl.Vec3{1, 1, 0}.Normalize()),
+			Scale:    mgl.Vec3{0.5, 2, 1.5},
+		}},
+	}
+
+	for _, test := range tests {
+		decomposed := DecomposeMatrix(test.transform.Matrix())
+		if !nearVec3(decomposed.Position, test.transform.Position) {
+			t.Errorf("%s: position %v, want %v", test.name, decomposed.Position, test.transform.Position)
+		}
+		if !sameRotation(decomposed.Rotation, test.transform.Rotation) {
+			t.Errorf("%s: rotation %v, want %v", test.name, decomposed.Rotation, test.transform.Rotation)
+		}
+		if !nearVec3(decomposed.Scale, test.transform.Scale) {
+			t.Errorf("%s: scale %v, want %v", test.name, decomposed.Scale, test.transform.Scale)
+		}
+		if !nearMat4(decomposed.Matrix(), test.transform.Matrix()) {
+			t.Errorf("%s: matrix %v, want %v", test.name, decomposed.Matrix(), test.transform.Matrix())
+		}
+	}
+}
+
+func TestDecomposeMirroredMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix mgl.Mat4
+	}{
+		{"mirrored in x", mgl.Scale3D(-1, 1, 1)},
+		{"mirrored in y", mgl.Scale3D(1, -1, 1)},
+		{"mirrored in z", mgl.Scale3D(1, 1, -1)},
+		{"mirrored, rotated and moved", mgl.Translate3D(1, 2, 3).Mul4(mgl.HomogRotate3D(0.5, mgl.Vec3{0, 0, 1})).Mul4(mgl.Scale3D(2, -3, 4))},
+	}
+
+	for _, test := range tests {
+		decomposed := DecomposeMatrix(test.matrix)
+		if decomposed.Scale[0] >= 0 || decomposed.Scale[1] <= 0 || decomposed.Scale[2] <= 0 {
+			t.Errorf("%s: scale %v, want only x negative", test.name, decomposed.Scale)
+		}
+		if !nearMat4(decomposed.Matrix(), test.matrix) {
+			t.Errorf("%s: matrix %v, want %v", test.name, decomposed.Matrix(), test.matrix)
+		}
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	identity := mgl.QuatIdent()
+	quarter := mgl.QuatRotate(math.Pi/2, mgl.Vec3{0, 1, 0})
+	twoThirds := mgl.QuatRotate(2*math.Pi/3, mgl.Vec3{0, 1, 0})
+	tilted := mgl.QuatRotate(2, mgl.Vec3{1, 2, 3}.Normalize())
+	negated := func(q mgl.Quat) mgl.Quat {
+		return mgl.Quat{W: -q.W, V: q.V.Mul(-1)}
+	}
+
+	tests := []struct {
+		name string
+		a, b mgl.Quat
+		f    float32
+		want mgl.Quat
+	}{
+		{"start", identity, quarter, 0, identity},
+		{"end", identity, quarter, 1, quarter},
+		{"halfway", identity, quarter, 0.5, mgl.QuatRotate(math.Pi/4, mgl.Vec3{0, 1, 0})},
+		{"halfway to two thirds of a turn", identity, twoThirds, 0.5, mgl.QuatRotate(math.Pi/3, mgl.Vec3{0, 1, 0})},
+		{"end of tilted", identity, tilted, 1, tilted},
+		// -q is the same rotation as q, nothing to interpolate
+		{"antipodal start", tilted, negated(tilted), 0, tilted},
+		{"antipodal halfway", tilted, negated(tilted), 0.5, tilted},
+		{"antipodal end", tilted, negated(tilted), 1, tilted},
+		// the short way from the identity to -quarter is still a quarter turn
+		{"negated end halfway", identity, negated(quarter), 0.5, mgl.QuatRotate(math.Pi/4, mgl.Vec3{0, 1, 0})},
+	}
+
+	for _, test := range tests {
+		got := slerp(test.a, test.b, test.f)
+		if !sameRotation(got, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, got, test.want)
+		}
+		if !near(got.Len(), 1) {
+			t.Errorf("%s: length %v, want 1", test.name, got.Len())
+		}
+	}
+}
+
+func TestQuatFromBasis(t *testing.T) {
+	// half turns make each of the four branches pick the largest diagonal element
+	tests := []struct {
+		name     string
+		rotation mgl.Quat
+	}{
+		{"identity", mgl.QuatIdent()},
+		{"small rotation", mgl.QuatRotate(0.3, mgl.Vec3{1, 2, 3}.Normalize())},
+		{"half turn around x", mgl.QuatRotate(math.Pi, mgl.Vec3{1, 0, 0})},
+		{"half turn around y", mgl.QuatRotate(math.Pi, mgl.Vec3{0, 1, 0})},
+		{"half turn around z", mgl.QuatRotate(math.Pi, mgl.Vec3{0, 0, 1})},
+		{"large tilted rotation", mgl.QuatRotate(3, mgl.Vec3{-1, 0.5, 2}.Normalize())},
+	}
+
+	for _, test := range tests {
+		q := test.rotation
+		got := quatFromBasis(q.Rotate(mgl.Vec3{1, 0, 0}), q.Rotate(mgl.Vec3{0, 1, 0}), q.Rotate(mgl.Vec3{0, 0, 1}))
+		if !sameRotation(got, q) {
+			t.Errorf("%s: %v, want %v", test.name, got, q)
+		}
+	}
+}
+
+func TestTransformInverse(t *testing.T) {
+	transform := Transform{
+		Position: mgl.Vec3{1, 2, 3},
+		Rotation: mgl.QuatRotate(0.8, mgl.Vec3{0, 1, 1}.Normalize()),
+		Scale:    mgl.Vec3{2, 2, 2},
+	}
+	p := mgl.Vec3{-4, 5, 0.5}
+
+	if got := transform.Inverse().Point(transform.Point(p)); !nearVec3(got, p) {
+		t.Errorf("inverse of point %v, want %v", got, p)
+	}
+	if got := transform.Mul(transform.Inverse()).Matrix(); !nearMat4(got, mgl.Ident4()) {
+		t.Errorf("transform times its inverse %v, want identity", got)
+	}
+	if got, want := transform.Point(p), mgl.TransformCoordinate(p, transform.Matrix()); !nearVec3(got, want) {
+		t.Errorf("point %v, matrix gives %v", got, want)
+	}
+}
+
+func TestLookAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		position mgl.Vec3
+		target   mgl.Vec3
+		up       mgl.Vec3
+	}{
+		{"along -z", mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 0, -5}, mgl.Vec3{0, 1, 0}},
+		{"sideways", mgl.Vec3{1, 2, 3}, mgl.Vec3{4, 2, 3}, mgl.Vec3{0, 1, 0}},
+		{"up parallel to the view", mgl.Vec3{0, 0, 0}, mgl.Vec3{0, 10, 0}, mgl.Vec3{0, 1, 0}},
+	}
+
+	for _, test := range tests {
+		transform := TransformAt(test.position).LookAt(test.target, test.up)
+		want := test.target.Sub(test.position).Normalize()
+		if got := transform.Direction(mgl.Vec3{0, 0, -1}); !nearVec3(got, want) {
+			t.Errorf("%s: -z turned to %v, want %v", test.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package _includes
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl"
+	"github.com/go-gl/glh"
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// binding point of the per frame uniform block
+const FrameBinding = 0
+
+// per frame data shared by all programs, matches FrameUniformsShaderSource
+type FrameUniforms struct {
+	View           mgl.Mat4
+	Projection     mgl.Mat4
+	CameraPosition mgl.Vec3
+	Time           float32
+}
+
+// uniform block declaration for FrameUniforms, insert right after the #version line,
+// programs using it must not declare view or projection uniforms themselves
+const FrameUniformsShaderSource = `
+		#extension GL_ARB_uniform_buffer_object : require
+		layout(std140) uniform Frame {
+			mat4 view;
+			mat4 projection;
+			vec3 cameraPosition;
+			float time;
+		};
+`
+
+type UniformBuffer struct {
+	Buffer  gl.Buffer
+	Binding uint
+	Size    int
+	// contexts the binding point is set up in
+	bound map[int]bool
+}
+
+// allocates a uniform buffer for data, which has to be a pointer to a struct laid out by std140 rules,
+// the buffer is bound to the given binding point right away
+func NewUniformBuffer(data interface{}, binding uint) *UniformBuffer {
+	size, err := Std140Size(data)
+	if err != nil {
+		panic(err)
+	}
+
+	ub := &UniformBuffer{
+		Buffer:  genBuffer(),
+		Binding: binding,
+		Size:    size,
+		bound:   make(map[int]bool),
+	}
+
+	State.BindBuffer(gl.UNIFORM_BUFFER, ub.Buffer)
+	gl.BufferData(gl.UNIFORM_BUFFER, size, nil, gl.DYNAMIC_DRAW)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, int(reflect.TypeOf(data).Elem().Size()), data)
+
+	State.BindBufferBase(gl.UNIFORM_BUFFER, binding, ub.Buffer)
+	ub.bound[currentContext] = true
+	glh.OpenGLSentinel()
+
+	return ub
+}
+
+// connects the named uniform block of program to the binding point of this buffer
+func (ub *UniformBuffer) BindProgram(program gl.Program, blockName string) {
+	index := program.GetUniformBlockIndex(blockName)
+	if index == gl.INVALID_INDEX {
+		return
+	}
+	program.UniformBlockBinding(index, ub.Binding)
+	glh.OpenGLSentinel()
+}
+
+// uploads data, which has to be of the same type the buffer was created with
+func (ub *UniformBuffer) Update(data interface{}) {
+	size := int(reflect.TypeOf(data).Elem().Size())
+	if size > ub.Size {
+		panic("uniform data is larger than the uniform buffer!")
+	}
+
+	if !ub.bound[currentContext] {
+		// binding points belong to the context
+		State.BindBufferBase(gl.UNIFORM_BUFFER, ub.Binding, ub.Buffer)
+		ub.bound[currentContext] = true
+	}
+	State.BindBuffer(gl.UNIFORM_BUFFER, ub.Buffer)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, size, data)
+	glh.OpenGLSentinel()
+}
+
+var (
+	vec2Type = reflect.TypeOf(mgl.Vec2{})
+	vec3Type = reflect.TypeOf(mgl.Vec3{})
+	vec4Type = reflect.TypeOf(mgl.Vec4{})
+	mat2Type = reflect.TypeOf(mgl.Mat2{})
+	mat3Type = reflect.TypeOf(mgl.Mat3{})
+	mat4Type = reflect.TypeOf(mgl.Mat4{})
+)
+
+// checks that the Go memory layout of the struct data points to matches std140
+// and returns the size of the uniform block, blank (_) fields are treated as padding
+func Std140Size(data interface{}) (int, error) {
+	t := reflect.TypeOf(data)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return 0, fmt.Errorf("std140: expected a pointer to a struct, got %v", t)
+	}
+
+	size, _, err := std140(t.Elem(), t.Elem().Name())
+	return size, err
+}
+
+func roundUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// returns std140 size and base alignment of t
+func std140(t reflect.Type, name string) (int, int, error) {
+	switch t {
+	case vec2Type:
+		return 8, 8, nil
+	case vec3Type:
+		return 12, 16, nil
+	case vec4Type:
+		return 16, 16, nil
+	case mat4Type:
+		return 64, 16, nil
+	case mat2Type:
+		return 0, 0, fmt.Errorf("std140: %s: mat2 columns are padded to 16 bytes, use [2]mgl.Vec4 instead", name)
+	case mat3Type:
+		return 0, 0, fmt.Errorf("std140: %s: mat3 columns are padded to 16 bytes, use [3]mgl.Vec4 instead", name)
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Int32, reflect.Uint32:
+		return 4, 4, nil
+
+	case reflect.Array:
+		// arrays of scalars and vectors have a stride of 16 bytes per element
+		size, align, err := std140(t.Elem(), name+"[]")
+		if err != nil {
+			return 0, 0, err
+		}
+		stride := roundUp(size, 16)
+		if stride != int(t.Elem().Size()) {
+			return 0, 0, fmt.Errorf("std140: %s: array elements need a stride of %d bytes, but are %d bytes apart", name, stride, t.Elem().Size())
+		}
+		if align < 16 {
+			align = 16
+		}
+		return stride * t.Len(), align, nil
+
+	case reflect.Struct:
+		offset, align := 0, 16
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Name == "_" {
+				continue
+			}
+
+			fieldName := name + "." + field.Name
+			size, fieldAlign, err := std140(field.Type, fieldName)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			expected := roundUp(offset, fieldAlign)
+			if int(field.Offset) != expected {
+				return 0, 0, fmt.Errorf("std140: %s is at offset %d but has to be at %d, adjust the padding before it by %d bytes",
+					fieldName, field.Offset, expected, expected-int(field.Offset))
+			}
+			offset = expected + size
+			if fieldAlign > align {
+				align = fieldAlign
+			}
+		}
+		return roundUp(offset, align), align, nil
+	}
+
+	return 0, 0, fmt.Errorf("std140: %s: unsupported type %v", name, t)
+}
+
+func (ub *UniformBuffer) Delete() {
+	Resources.Release(ub.Buffer)
+	ub.Buffer = 0
+}
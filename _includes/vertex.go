@@ -22,6 +22,12 @@ type TextureVertex struct {
 	TextureCoordinate mgl.Vec2
 }
 
+type ColorTextureVertex struct {
+	Position          mgl.Vec4
+	TextureCoordinate mgl.Vec2
+	Color             mgl.Vec4
+}
+
 type NormalVertex struct {
 	Position mgl.Vec4
 	Color    mgl.Vec4
@@ -41,6 +47,8 @@ type ColorVertices []ColorVertex
 
 type TextureVertices []TextureVertex
 
+type ColorTextureVertices []ColorTextureVertex
+
 type NormalVertices []NormalVertex
 
 type NormalTextureVertices []NormalTextureVertex
@@ -52,9 +60,134 @@ func init() {
 		panic("wrong color vertex size!")
 	} else if int(glh.Sizeof(gl.FLOAT))*6 != int(unsafe.Sizeof(TextureVertex{})) {
 		panic("wrong texture vertex size!")
+	} else if int(glh.Sizeof(gl.FLOAT))*10 != int(unsafe.Sizeof(ColorTextureVertex{})) {
+		panic("wrong color texture vertex size!")
 	} else if int(glh.Sizeof(gl.FLOAT))*11 != int(unsafe.Sizeof(NormalVertex{})) {
 		panic("wrong normal vertex size!")
 	} else if int(glh.Sizeof(gl.FLOAT))*13 != int(unsafe.Sizeof(NormalTextureVertex{})) {
 		panic("wrong normal texture vertex size!")
 	}
 }
+
+type VertexAttribute struct {
+	Name   string
+	Length uint
+	Offset uintptr
+}
+
+type VertexLayout struct {
+	Stride     int
+	Attributes []VertexAttribute
+}
+
+var (
+	vec4Size = unsafe.Sizeof(mgl.Vec4{})
+	vec3Size = unsafe.Sizeof(mgl.Vec3{})
+
+	VertexLayoutPosition = VertexLayout{
+		Stride: int(unsafe.Sizeof(Vertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+		},
+	}
+	VertexLayoutColor = VertexLayout{
+		Stride: int(unsafe.Sizeof(ColorVertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+			{"color", 4, vec4Size},
+		},
+	}
+	VertexLayoutTexture = VertexLayout{
+		Stride: int(unsafe.Sizeof(TextureVertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+			{"textureCoordinate", 2, vec4Size},
+		},
+	}
+	VertexLayoutColorTexture = VertexLayout{
+		Stride: int(unsafe.Sizeof(ColorTextureVertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+			{"textureCoordinate", 2, vec4Size},
+			{"color", 4, vec4Size + unsafe.Sizeof(mgl.Vec2{})},
+		},
+	}
+	VertexLayoutNormal = VertexLayout{
+		Stride: int(unsafe.Sizeof(NormalVertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+			{"color", 4, vec4Size},
+			{"norm", 3, vec4Size * 2},
+		},
+	}
+	VertexLayoutNormalTexture = VertexLayout{
+		Stride: int(unsafe.Sizeof(NormalTextureVertex{})),
+		Attributes: []VertexAttribute{
+			{"position", 4, 0},
+			{"color", 4, vec4Size},
+			{"norm", 3, vec4Size * 2},
+			{"textureCoordinate", 2, vec4Size*2 + vec3Size},
+		},
+	}
+)
+
+// returns the attribute layout and the number of vertices of a vertex slice
+func LayoutOf(vertices interface{}) (VertexLayout, int) {
+	switch v := vertices.(type) {
+	case Vertices:
+		return VertexLayoutPosition, len(v)
+	case ColorVertices:
+		return VertexLayoutColor, len(v)
+	case TextureVertices:
+		return VertexLayoutTexture, len(v)
+	case ColorTextureVertices:
+		return VertexLayoutColorTexture, len(v)
+	case NormalVertices:
+		return VertexLayoutNormal, len(v)
+	case NormalTextureVertices:
+		return VertexLayoutNormalTexture, len(v)
+	}
+	panic("unknown vertex type provided!")
+}
+
+// returns the positions of a vertex slice, divided by w
+func PositionsOf(vertices interface{}) []mgl.Vec3 {
+	var positions []mgl.Vec4
+	switch v := vertices.(type) {
+	case Vertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	case ColorVertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	case TextureVertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	case ColorTextureVertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	case NormalVertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	case NormalTextureVertices:
+		for _, vertex := range v {
+			positions = append(positions, vertex.Position)
+		}
+	default:
+		panic("unknown vertex type provided!")
+	}
+
+	result := make([]mgl.Vec3, len(positions))
+	for i, p := range positions {
+		if p[3] != 0 {
+			p = p.Mul(1 / p[3])
+		}
+		result[i] = mgl.Vec3{p[0], p[1], p[2]}
+	}
+	return result
+}
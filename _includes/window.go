@@ -0,0 +1,252 @@
+package _includes
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl"
+	glfw "github.com/go-gl/glfw3"
+)
+
+type WindowMode int
+
+const (
+	Windowed WindowMode = iota
+	// exclusive fullscreen, switches the monitor to the configured video mode
+	Fullscreen
+	// fullscreen window keeping the current video mode of the monitor
+	Borderless
+)
+
+type WindowConfig struct {
+	Mode WindowMode
+	// index into glfw.GetMonitors for fullscreen and borderless, 0 is the primary monitor
+	Monitor int
+	// video mode for fullscreen, zero keeps the current one of the monitor
+	VideoWidth  int
+	VideoHeight int
+	RefreshRate int
+	Resizable   bool
+	Decorated   bool
+	VSync       bool
+	// multisampling, 0 disables it
+	Samples int
+	SRGB    bool
+	// context version, 0.0 takes whatever the driver offers,
+	// Core requests a forward compatible core profile of at least 3.3
+	Major int
+	Minor int
+	Core  bool
+}
+
+// settings NewApp creates its window with, change them before creating the App
+var WindowSettings = WindowConfig{
+	Resizable: true,
+	Decorated: true,
+	VSync:     true,
+}
+
+// every window has its own context, all of them share buffers, textures and programs with the first one.
+// Container objects (vertex arrays and framebuffers) aren't shared, they are kept per context id,
+// ids are never reused so recreated windows start without any.
+var (
+	currentContext int
+	contextCount   int
+	contexts       = make(map[int]*App)
+)
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
+
+func monitorAt(index int) (*glfw.Monitor, error) {
+	if index == 0 {
+		return glfw.GetPrimaryMonitor()
+	}
+
+	monitors, err := glfw.GetMonitors()
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(monitors) {
+		return nil, fmt.Errorf("there is no monitor %d, only %d connected", index, len(monitors))
+	}
+	return monitors[index], nil
+}
+
+func createWindow(config WindowConfig, width, height int, title string, share *glfw.Window) (*glfw.Window, error) {
+	glfw.DefaultWindowHints()
+	glfw.WindowHint(glfw.Resizable, glfwBool(config.Resizable))
+	glfw.WindowHint(glfw.Decorated, glfwBool(config.Decorated))
+	glfw.WindowHint(glfw.Samples, config.Samples)
+	glfw.WindowHint(glfw.SrgbCapable, glfwBool(config.SRGB))
+
+	major, minor := config.Major, config.Minor
+	if config.Core && (major < 3 || major == 3 && minor < 3) {
+		major, minor = 3, 3
+	}
+	if major > 0 {
+		glfw.WindowHint(glfw.ContextVersionMajor, major)
+		glfw.WindowHint(glfw.ContextVersionMinor, minor)
+	}
+	if config.Core {
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	}
+	if DebugOutput.Enabled {
+		// drivers may report little or nothing outside of debug contexts
+		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+	}
+
+	var monitor *glfw.Monitor
+	if config.Mode != Windowed {
+		var err error
+		monitor, err = monitorAt(config.Monitor)
+		if err != nil {
+			return nil, err
+		}
+		mode, err := monitor.GetVideoMode()
+		if err != nil {
+			return nil, err
+		}
+
+		if config.Mode == Borderless {
+			// matching the current mode keeps the monitor from switching
+			glfw.WindowHint(glfw.RedBits, mode.RedBits)
+			glfw.WindowHint(glfw.GreenBits, mode.GreenBits)
+			glfw.WindowHint(glfw.BlueBits, mode.BlueBits)
+			glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+			width, height = mode.Width, mode.Height
+		} else {
+			width, height = mode.Width, mode.Height
+			if config.VideoWidth > 0 && config.VideoHeight > 0 {
+				width, height = config.VideoWidth, config.VideoHeight
+			}
+			if config.RefreshRate > 0 {
+				glfw.WindowHint(glfw.RefreshRate, config.RefreshRate)
+			}
+		}
+	}
+
+	return glfw.CreateWindow(width, height, title, monitor, share)
+}
+
+// makes the App's context current and switches State to its cache
+func (a *App) makeCurrent() {
+	if currentContext == a.context {
+		return
+	}
+	a.Window.MakeContextCurrent()
+	currentContext = a.context
+	State = a.state
+}
+
+// runs f with the given context current, does nothing if it is gone already
+func withContext(context int, f func()) {
+	if context == 0 || context == currentContext {
+		f()
+		return
+	}
+	a, ok := contexts[context]
+	if !ok {
+		return
+	}
+
+	previous := contexts[currentContext]
+	a.makeCurrent()
+	f()
+	if previous != nil {
+		previous.makeCurrent()
+	}
+}
+
+// gives the window a new context id, makes it current and applies the state every App starts with
+func (a *App) setupContext() {
+	contextCount++
+	a.context = contextCount
+	contexts[a.context] = a
+	currentContext = 0
+	a.makeCurrent()
+	if a.Config.VSync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+
+	if gl.Init() != 0 {
+		panic("can't init glew!")
+	}
+	// glew provokes an invalid enum error on core profiles
+	gl.GetError()
+
+	State.Invalidate()
+	if !a.Config.Core && a.RenderState.LineWidth == 0 {
+		// wide lines are deprecated
+		a.RenderState.LineWidth = 3
+	}
+	a.RenderState.Apply()
+	if a.Config.Samples > 0 {
+		State.Enable(gl.MULTISAMPLE)
+	}
+	if a.Config.SRGB {
+		State.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+}
+
+// switches between windowed, fullscreen and borderless at runtime, Alt+Enter toggles fullscreen.
+// GLFW can't move a window onto a monitor, so a new window sharing the context is created and
+// the old one destroyed, vertex arrays and framebuffers are recreated on their next use.
+// Must not be called from glfw callbacks.
+func (a *App) SetWindowMode(mode WindowMode) {
+	if mode == a.Config.Mode {
+		return
+	}
+
+	if a.Config.Mode == Windowed {
+		a.windowed[0], a.windowed[1] = a.Window.GetPosition()
+		a.windowed[2], a.windowed[3] = a.Window.GetSize()
+	}
+
+	config := a.Config
+	config.Mode = mode
+	window, err := createWindow(config, a.windowed[2], a.windowed[3], a.Title, a.Window)
+	if err != nil {
+		panic(err)
+	}
+	if mode == Windowed {
+		window.SetPosition(a.windowed[0], a.windowed[1])
+	}
+
+	old := a.Window
+	delete(apps, old)
+	a.Window = window
+	a.Config = config
+	apps[window] = a
+	a.setCallbacks()
+	a.sizeChanged()
+
+	old.Destroy()
+	a.contextLost()
+
+	a.setupContext()
+	if a.Debugging {
+		a.Debugging = EnableDebugOutput(reportDebugMessage)
+	}
+}
+
+func (a *App) ToggleFullscreen() {
+	if a.Config.Mode == Windowed {
+		a.SetWindowMode(Fullscreen)
+	} else {
+		a.SetWindowMode(Windowed)
+	}
+}
+
+// everything not shared with the new context is gone
+func (a *App) contextLost() {
+	Resources.dropContainers(a.context)
+	delete(contexts, a.context)
+	State.Invalidate()
+}